@@ -0,0 +1,329 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"io"
+	"strings"
+)
+
+// Number of attempts of an update or a delete without an explicit version
+const updateAttempts = 3
+
+// stringList is a flag that can be repeated
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// attributeFlags holds the account attributes that can be set with flags
+type attributeFlags struct {
+	accountNumber         string
+	accountClassification string
+	alternativeNames      stringList
+	bankID                string
+	bankIDCode            string
+	baseCurrency          string
+	bic                   string
+	country               string
+	iban                  string
+	name                  stringList
+	status                string
+}
+
+func (a *attributeFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&a.accountNumber, "account-number", "", "account number")
+	flags.StringVar(&a.accountClassification, "account-classification", "", "Personal or Business")
+	flags.Var(&a.alternativeNames, "alternative-name", "alternative name of the holder, can be repeated")
+	flags.StringVar(&a.bankID, "bank-id", "", "bank ID, e.g. a sort code")
+	flags.StringVar(&a.bankIDCode, "bank-id-code", "", "bank ID code, e.g. GBDSC")
+	flags.StringVar(&a.baseCurrency, "base-currency", "", "ISO 4217 currency code")
+	flags.StringVar(&a.bic, "bic", "", "BIC of the bank")
+	flags.StringVar(&a.country, "country", "", "ISO 3166-1 alpha-2 country code")
+	flags.StringVar(&a.iban, "iban", "", "IBAN")
+	flags.Var(&a.name, "name", "name of the holder, can be repeated")
+	flags.StringVar(&a.status, "status", "", "account status")
+}
+
+// apply sets the attributes given with flags, leaving the others untouched
+func (a *attributeFlags) apply(attributes *model.AccountAttributes) {
+	set := func(target *string, value string) {
+		if value != "" {
+			*target = value
+		}
+	}
+
+	set(&attributes.AccountNumber, a.accountNumber)
+	set(&attributes.AccountClassification, a.accountClassification)
+	set(&attributes.BankID, a.bankID)
+	set(&attributes.BankIDCode, a.bankIDCode)
+	set(&attributes.BaseCurrency, a.baseCurrency)
+	set(&attributes.Bic, a.bic)
+	set(&attributes.Country, a.country)
+	set(&attributes.Iban, a.iban)
+	set(&attributes.Status, a.status)
+
+	if len(a.alternativeNames) > 0 {
+		attributes.AlternativeNames = a.alternativeNames
+	}
+
+	if len(a.name) > 0 {
+		attributes.Name = a.name
+	}
+}
+
+// runAccounts runs an accounts command
+func (c *cli) runAccounts(command string, args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("form3 accounts "+command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var run func() error
+
+	switch command {
+	case "create":
+		run = c.createCommand(flags)
+	case "fetch":
+		run = c.fetchCommand(flags)
+	case "list":
+		run = c.listCommand(flags)
+	case "delete":
+		run = c.deleteCommand(flags)
+	case "update":
+		run = c.updateCommand(flags)
+	default:
+		fmt.Fprintf(stderr, "unknown accounts command %q, expected create, fetch, list, delete or update\n", command)
+		return errUsage
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected arguments: %s\n", strings.Join(flags.Args(), " "))
+		return errUsage
+	}
+
+	return run()
+}
+
+func (c *cli) createCommand(flags *flag.FlagSet) func() error {
+	file := flags.String("file", "", "JSON or YAML file with the account, - reads from stdin")
+	id := flags.String("id", "", "account ID, generated when empty")
+	organisationID := flags.String("organisation-id", "", "organisation ID")
+	var attributes attributeFlags
+	attributes.register(flags)
+
+	return func() error {
+		var account model.Account
+
+		if *file != "" {
+			if err := readPayload(*file, c.stdin, &account); err != nil {
+				return err
+			}
+		}
+
+		if *id != "" {
+			parsed, err := parseUUID("id", *id)
+
+			if err != nil {
+				return err
+			}
+
+			account.ID = parsed
+		}
+
+		if *organisationID != "" {
+			parsed, err := parseUUID("organisation-id", *organisationID)
+
+			if err != nil {
+				return err
+			}
+
+			account.OrganisationID = parsed
+		}
+
+		if account.ID == uuid.Nil {
+			account.ID = uuid.New()
+		}
+
+		if account.Type == "" {
+			account.Type = "accounts"
+		}
+
+		attributes.apply(&account.Attributes)
+
+		ctx, cancel := c.context()
+		defer cancel()
+
+		response, err := c.f3.Accounts.CreateWithContext(ctx, &model.AccountCreateRequest{Data: account})
+
+		if err != nil {
+			return err
+		}
+
+		return c.printer.printAccounts(response, response.Data)
+	}
+}
+
+func (c *cli) fetchCommand(flags *flag.FlagSet) func() error {
+	id := flags.String("id", "", "account ID")
+
+	return func() error {
+		accountID, err := parseUUID("id", *id)
+
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := c.context()
+		defer cancel()
+
+		response, err := c.f3.Accounts.FetchWithContext(ctx, accountID)
+
+		if err != nil {
+			return err
+		}
+
+		return c.printer.printAccounts(response, response.Data)
+	}
+}
+
+func (c *cli) listCommand(flags *flag.FlagSet) func() error {
+	opts := &accounts.ListOptions{Filter: &accounts.Filter{}}
+	flags.IntVar(&opts.PageNumber, "page-number", 0, "page number")
+	flags.IntVar(&opts.PageSize, "page-size", 0, "page size")
+	flags.StringVar(&opts.Filter.BankID, "bank-id", "", "filter by bank ID")
+	flags.StringVar(&opts.Filter.BankIDCode, "bank-id-code", "", "filter by bank ID code")
+	flags.StringVar(&opts.Filter.AccountNumber, "account-number", "", "filter by account number")
+	flags.StringVar(&opts.Filter.IBAN, "iban", "", "filter by IBAN")
+	flags.StringVar(&opts.Filter.CustomerID, "customer-id", "", "filter by customer ID")
+	flags.StringVar(&opts.Filter.Country, "country", "", "filter by country")
+	all := flags.Bool("all", false, "list all the pages starting from page-number")
+
+	return func() error {
+		ctx, cancel := c.context()
+		defer cancel()
+
+		if !*all {
+			response, err := c.f3.Accounts.ListWithContext(ctx, opts)
+
+			if err != nil {
+				return err
+			}
+
+			return c.printer.printAccounts(response, response.Data...)
+		}
+
+		var listed []model.Account
+		it := c.f3.Accounts.ListAllWithContext(ctx, opts)
+
+		for it.Next() {
+			listed = append(listed, it.Account())
+		}
+
+		if err := it.Err(); err != nil {
+			return err
+		}
+
+		return c.printer.printAccounts(model.AccountListResponse{Data: listed}, listed...)
+	}
+}
+
+func (c *cli) deleteCommand(flags *flag.FlagSet) func() error {
+	id := flags.String("id", "", "account ID")
+	version := flags.Int("version", -1, "current version of the account, the latest version is used when omitted")
+
+	return func() error {
+		accountID, err := parseUUID("id", *id)
+
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := c.context()
+		defer cancel()
+
+		if *version < 0 {
+			return c.f3.Accounts.DeleteLatestWithContext(ctx, accountID, updateAttempts)
+		}
+
+		return c.f3.Accounts.DeleteWithContext(ctx, accountID, *version)
+	}
+}
+
+func (c *cli) updateCommand(flags *flag.FlagSet) func() error {
+	id := flags.String("id", "", "account ID")
+	version := flags.Int("version", -1, "current version of the account, the latest version is used when omitted")
+	file := flags.String("file", "", "JSON or YAML file with the attributes to change, - reads from stdin")
+	var attributes attributeFlags
+	attributes.register(flags)
+
+	return func() error {
+		accountID, err := parseUUID("id", *id)
+
+		if err != nil {
+			return err
+		}
+
+		var changes model.AccountAttributes
+
+		if *file != "" {
+			if err := readPayload(*file, c.stdin, &changes); err != nil {
+				return err
+			}
+		}
+
+		attributes.apply(&changes)
+
+		ctx, cancel := c.context()
+		defer cancel()
+
+		var response *model.AccountApiResponse
+
+		if *version >= 0 {
+			response, err = c.f3.Accounts.UpdateWithContext(ctx, &model.AccountUpdateRequest{Data: model.Account{
+				Attributes: changes,
+				ID:         accountID,
+				Type:       "accounts",
+				Version:    model.Version(*version),
+			}})
+		} else {
+			response, err = c.f3.Accounts.MutateWithContext(ctx, accountID, updateAttempts, func(account *model.Account) error {
+				// Only the changes are sent, the API keeps the other attributes
+				account.Attributes = changes
+				return nil
+			})
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return c.printer.printAccounts(response, response.Data)
+	}
+}
+
+// parseUUID parses a UUID given with a flag
+func parseUUID(name string, value string) (uuid.UUID, error) {
+	if value == "" {
+		return uuid.Nil, fmt.Errorf("%w: -%s is required", errUsage, name)
+	}
+
+	id, err := uuid.Parse(value)
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: -%s: %v", errUsage, name, err)
+	}
+
+	return id, nil
+}
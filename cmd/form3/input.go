@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+)
+
+// readPayload reads a JSON or YAML payload from a file, or from stdin when path is -, into target.
+// A payload wrapped in a data object, as in the API requests, is unwrapped
+func readPayload(path string, stdin io.Reader, target interface{}) error {
+	var content []byte
+	var err error
+
+	if path == "-" {
+		content, err = ioutil.ReadAll(stdin)
+	} else {
+		content, err = ioutil.ReadFile(path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: reading %s: %v", errUsage, path, err)
+	}
+
+	// YAML is a superset of JSON so both formats are decoded the same way
+	var decoded interface{}
+
+	if err := yaml.Unmarshal(content, &decoded); err != nil {
+		return fmt.Errorf("%w: decoding %s: %v", errUsage, path, err)
+	}
+
+	if wrapper, ok := decoded.(map[string]interface{}); ok {
+		if data, ok := wrapper["data"]; ok {
+			decoded = data
+		}
+	}
+
+	// The models only have JSON tags, so the payload goes through JSON
+	encoded, err := json.Marshal(decoded)
+
+	if err != nil {
+		return fmt.Errorf("%w: decoding %s: %v", errUsage, path, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("%w: decoding %s: %v", errUsage, path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// Command form3 runs operations against Form3's accounts API
+//
+//	form3 [-base-url URL] [-output table|json|yaml] [-timeout 30s] accounts <create|fetch|list|delete|update> [flags]
+//
+// Exit codes:
+//
+//	0 success
+//	1 unexpected error
+//	2 invalid usage
+//	3 validation failure, either client side or reported by the API
+//	4 resource not found
+//	5 conflict, e.g. a stale version or a duplicate account
+//	6 API server error
+//	7 network error or timeout
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/form3"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Exit codes
+const (
+	exitOK = iota
+	exitError
+	exitUsage
+	exitValidation
+	exitNotFound
+	exitConflict
+	exitServerError
+	exitNetworkError
+)
+
+const defaultBaseURL = "http://localhost:8080/"
+
+// errUsage is returned when the command line is invalid
+var errUsage = errors.New("invalid usage")
+
+// cli holds the global options shared by every command
+type cli struct {
+	f3      *form3.FormResources
+	printer *printer
+	timeout time.Duration
+	stdin   io.Reader
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("form3", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: form3 [flags] accounts <create|fetch|list|delete|update> [flags]")
+		flags.PrintDefaults()
+	}
+
+	baseURL := flags.String("base-url", envOrDefault("FORM3_BASE_URL", defaultBaseURL), "base URL of the API, defaults to $FORM3_BASE_URL")
+	output := flags.String("output", "table", "output format: table, json or yaml")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout of the whole operation")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	p, err := newPrinter(*output, stdout)
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	bu, err := url.Parse(*baseURL)
+
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid base URL: %v\n", err)
+		return exitUsage
+	}
+
+	c := &cli{
+		f3:      form3.New(bu),
+		printer: p,
+		timeout: *timeout,
+		stdin:   stdin,
+	}
+
+	if flags.NArg() < 2 || flags.Arg(0) != "accounts" {
+		flags.Usage()
+		return exitUsage
+	}
+
+	err = c.runAccounts(flags.Arg(1), flags.Args()[2:], stderr)
+
+	// A bare errUsage means the flag package already printed the problem
+	if err != nil && err != errUsage {
+		fmt.Fprintln(stderr, err)
+	}
+
+	return exitCode(err)
+}
+
+// context returns the context every operation runs with
+func (c *cli) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+// exitCode maps an error to the exit code of its class
+func exitCode(err error) int {
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case client.IsValidation(err), errors.Is(err, accounts.ErrInvalidFilter):
+		return exitValidation
+	case client.IsNotFound(err):
+		return exitNotFound
+	case client.IsConflict(err):
+		return exitConflict
+	case client.IsServerError(err):
+		return exitServerError
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return exitNetworkError
+	}
+
+	return exitError
+}
+
+// envOrDefault returns the value of an environment variable or a default
+func envOrDefault(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/fakeapi"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Runs the command line against the fake server and returns the exit code, stdout and stderr
+func runCLI(server *fakeapi.Server, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-base-url", server.BaseURL().String()}, args...)
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_Accounts(t *testing.T) {
+
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	organisationID := uuid.New().String()
+
+	t.Run("should create an account from flags", func(t *testing.T) {
+		accountID := uuid.New()
+
+		code, stdout, stderr := runCLI(server, "", "-output", "json", "accounts", "create",
+			"-id", accountID.String(),
+			"-organisation-id", organisationID,
+			"-country", "GB",
+			"-bank-id", "400300",
+			"-bank-id-code", "GBDSC",
+			"-name", "Samantha Holder",
+		)
+		require.Equal(t, exitOK, code, stderr)
+
+		var response model.AccountApiResponse
+		require.NoError(t, json.Unmarshal([]byte(stdout), &response))
+		assert.Equal(t, accountID, response.Data.ID)
+		assert.Equal(t, []string{"Samantha Holder"}, response.Data.Attributes.Name)
+	})
+
+	t.Run("should create an account from a YAML file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "account.yaml")
+		payload := "data:\n" +
+			"  organisation_id: " + organisationID + "\n" +
+			"  attributes:\n" +
+			"    country: GB\n" +
+			"    bank_id: \"400300\"\n" +
+			"    name: [Samantha Holder]\n"
+		require.NoError(t, ioutil.WriteFile(file, []byte(payload), 0600))
+
+		code, stdout, stderr := runCLI(server, "", "-output", "yaml", "accounts", "create", "-file", file)
+
+		require.Equal(t, exitOK, code, stderr)
+		assert.Contains(t, stdout, "bank_id: \"400300\"")
+		assert.Contains(t, stdout, "type: accounts")
+	})
+
+	t.Run("should create an account from JSON on stdin", func(t *testing.T) {
+		request := testUtils.GetAccountCreateRequest(uuid.New())
+		payload, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		code, stdout, stderr := runCLI(server, string(payload), "accounts", "create", "-file", "-")
+
+		require.Equal(t, exitOK, code, stderr)
+		assert.Contains(t, stdout, request.Data.ID.String())
+	})
+
+	t.Run("should fetch, update and delete an account", func(t *testing.T) {
+		accountID := uuid.New()
+		server.PutAccount(testUtils.GetAccountApiResponse(accountID).Data)
+
+		code, stdout, stderr := runCLI(server, "", "accounts", "fetch", "-id", accountID.String())
+		require.Equal(t, exitOK, code, stderr)
+		assert.Contains(t, stdout, "ACCOUNT NUMBER")
+		assert.Contains(t, stdout, accountID.String())
+
+		code, stdout, stderr = runCLI(server, "", "-output", "json", "accounts", "update", "-id", accountID.String(), "-name", "Samantha Holder-Smith")
+		require.Equal(t, exitOK, code, stderr)
+		assert.Contains(t, stdout, "Samantha Holder-Smith")
+
+		code, _, _ = runCLI(server, "", "accounts", "update", "-id", accountID.String(), "-version", "0", "-name", "Sam")
+		assert.Equal(t, exitConflict, code)
+
+		code, _, stderr = runCLI(server, "", "accounts", "delete", "-id", accountID.String(), "-version", "1")
+		require.Equal(t, exitOK, code, stderr)
+
+		code, _, _ = runCLI(server, "", "accounts", "fetch", "-id", accountID.String())
+		assert.Equal(t, exitNotFound, code)
+	})
+
+	t.Run("should delete the latest version without -version", func(t *testing.T) {
+		account := testUtils.GetAccountApiResponse(uuid.New()).Data
+		account.Version = 2
+		server.PutAccount(account)
+
+		code, _, stderr := runCLI(server, "", "accounts", "delete", "-id", account.ID.String())
+		require.Equal(t, exitOK, code, stderr)
+
+		code, _, _ = runCLI(server, "", "accounts", "fetch", "-id", account.ID.String())
+		assert.Equal(t, exitNotFound, code)
+	})
+
+	t.Run("should list the accounts", func(t *testing.T) {
+		server.Reset()
+
+		for i := 0; i < 3; i++ {
+			server.PutAccount(testUtils.GetAccountApiResponse(uuid.New()).Data)
+		}
+
+		code, stdout, stderr := runCLI(server, "", "-output", "json", "accounts", "list", "-all", "-page-size", "2", "-country", "GB")
+		require.Equal(t, exitOK, code, stderr)
+
+		var response model.AccountListResponse
+		require.NoError(t, json.Unmarshal([]byte(stdout), &response))
+		assert.Len(t, response.Data, 3)
+	})
+
+	t.Run("should map errors to exit codes", func(t *testing.T) {
+		code, _, _ := runCLI(server, "", "accounts", "list", "-country", "gb")
+		assert.Equal(t, exitValidation, code)
+
+		code, _, _ = runCLI(server, "", "accounts", "create", "-organisation-id", organisationID, "-name", "Samantha Holder")
+		assert.Equal(t, exitValidation, code)
+
+		code, _, _ = runCLI(server, "", "accounts", "create", "-id", uuid.New().String(), "-organisation-id", organisationID, "-country", "GB")
+		assert.Equal(t, exitValidation, code)
+
+		code, _, stderr := runCLI(server, "", "accounts", "fetch")
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "-id is required")
+
+		code, _, _ = runCLI(server, "", "accounts", "rename")
+		assert.Equal(t, exitUsage, code)
+
+		code, _, _ = runCLI(server, "", "-output", "xml", "accounts", "list")
+		assert.Equal(t, exitUsage, code)
+
+		server.InjectFault(http.MethodGet, fakeapi.AccountsPath, fakeapi.Fault{StatusCode: http.StatusInternalServerError, Times: 1})
+		code, _, _ = runCLI(server, "", "accounts", "list")
+		assert.Equal(t, exitServerError, code)
+
+		// Every request is reset as the transport replays a request that failed on a reused connection
+		server.InjectFault(http.MethodGet, fakeapi.AccountsPath, fakeapi.Fault{ResetConnection: true})
+		code, _, _ = runCLI(server, "", "accounts", "list")
+		assert.Equal(t, exitNetworkError, code)
+		server.ClearFaults()
+	})
+}
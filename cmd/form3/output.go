@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer prints API responses in the selected format
+type printer struct {
+	format string
+	out    io.Writer
+}
+
+func newPrinter(format string, out io.Writer) (*printer, error) {
+	switch format {
+	case "table", "json", "yaml":
+		return &printer{format: format, out: out}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output format %q, expected table, json or yaml", format)
+}
+
+// printAccounts prints the response as is in JSON or YAML, or the accounts as a table
+func (p *printer) printAccounts(response interface{}, accounts ...model.Account) error {
+	switch p.format {
+	case "json":
+		encoder := json.NewEncoder(p.out)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(response)
+	case "yaml":
+		// The models only have JSON tags, so the response goes through JSON to keep the same keys
+		encoded, err := json.Marshal(response)
+
+		if err != nil {
+			return err
+		}
+
+		var decoded interface{}
+
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			return err
+		}
+
+		encoder := yaml.NewEncoder(p.out)
+		defer encoder.Close()
+
+		return encoder.Encode(decoded)
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tVERSION\tCOUNTRY\tBANK ID\tBANK ID CODE\tBIC\tACCOUNT NUMBER\tIBAN\tNAME\tSTATUS")
+
+	for _, account := range accounts {
+		attributes := account.Attributes
+		fmt.Fprintln(w, strings.Join([]string{
+			account.ID.String(),
+			account.Version.String(),
+			attributes.Country,
+			attributes.BankID,
+			attributes.BankIDCode,
+			attributes.Bic,
+			attributes.AccountNumber,
+			attributes.Iban,
+			strings.Join(attributes.Name, " "),
+			attributes.Status,
+		}, "\t"))
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,344 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	bicPattern      = regexp.MustCompile(`^([A-Z]{6}[A-Z0-9]{2}|[A-Z]{6}[A-Z0-9]{5})$`)
+)
+
+// accountStore keeps the accounts in memory, in the order they were created
+type accountStore struct {
+	byID  map[uuid.UUID]model.Account
+	order []uuid.UUID
+}
+
+func newAccountStore() *accountStore {
+	return &accountStore{byID: map[uuid.UUID]model.Account{}}
+}
+
+// all returns the accounts in creation order
+func (st *accountStore) all() []model.Account {
+	accounts := make([]model.Account, 0, len(st.order))
+
+	for _, id := range st.order {
+		accounts = append(accounts, st.byID[id])
+	}
+
+	return accounts
+}
+
+func (st *accountStore) put(account model.Account) {
+	if _, ok := st.byID[account.ID]; !ok {
+		st.order = append(st.order, account.ID)
+	}
+
+	st.byID[account.ID] = account
+}
+
+func (st *accountStore) remove(id uuid.UUID) {
+	delete(st.byID, id)
+
+	for i, storedID := range st.order {
+		if storedID == id {
+			st.order = append(st.order[:i], st.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Accounts returns a snapshot of the stored accounts in creation order
+func (s *Server) Accounts() []model.Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.accounts.all()
+}
+
+// PutAccount stores an account as is, bypassing validation. Used to seed the server in tests
+func (s *Server) PutAccount(account model.Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts.put(account)
+}
+
+// handleAccounts serves the accounts collection
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.createAccount(w, r)
+	case http.MethodGet:
+		s.listAccounts(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
+
+// handleAccount serves a single account
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, err := uuid.Parse(rawID)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.fetchAccount(w, id)
+	case http.MethodPatch:
+		s.updateAccount(w, r, id)
+	case http.MethodDelete:
+		s.deleteAccount(w, r, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
+
+func (s *Server) createAccount(w http.ResponseWriter, r *http.Request) {
+	var request model.AccountCreateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	account := request.Data
+
+	if problems := validateAccount(account); len(problems) > 0 {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+strings.Join(problems, "\n"))
+		return
+	}
+
+	if _, ok := s.accounts.byID[account.ID]; ok {
+		writeError(w, http.StatusConflict, "Account cannot be created as it violates a duplicate constraint")
+		return
+	}
+
+	account.Version = 0
+	account.CreatedOn = s.timestamp()
+	account.ModifiedOn = account.CreatedOn
+	s.accounts.put(account)
+
+	writeJSON(w, http.StatusCreated, accountResponse(account))
+}
+
+func (s *Server) fetchAccount(w http.ResponseWriter, id uuid.UUID) {
+	account, ok := s.accounts.byID[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, accountResponse(account))
+}
+
+func (s *Server) updateAccount(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	var request model.AccountUpdateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	if request.Data.ID != id {
+		writeError(w, http.StatusBadRequest, "id in body does not match the id in the path")
+		return
+	}
+
+	account, ok := s.accounts.byID[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+
+	if request.Data.Version != account.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	updated := account
+	updated.Attributes = model.AccountAttributes{}
+
+	if err := mergeAttributes(account.Attributes, request.Data.Attributes, &updated.Attributes); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid attributes: %v", err))
+		return
+	}
+
+	if problems := validateAccount(updated); len(problems) > 0 {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+strings.Join(problems, "\n"))
+		return
+	}
+
+	updated.Version++
+	updated.ModifiedOn = s.timestamp()
+	s.accounts.put(updated)
+
+	writeJSON(w, http.StatusOK, accountResponse(updated))
+}
+
+func (s *Server) deleteAccount(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+
+	if err != nil || version < 0 {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+
+	account, ok := s.accounts.byID[id]
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if account.Version != model.Version(version) {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	s.accounts.remove(id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listAccounts serves a page of accounts matching the filter[...] parameters. filter[customer_id] is accepted
+// but not applied as accounts don't carry a customer ID
+func (s *Server) listAccounts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var matching []model.Account
+
+	for _, account := range s.accounts.all() {
+		if matchesFilter(account, query) {
+			matching = append(matching, account)
+		}
+	}
+
+	start, end, links, err := paginate(query, len(matching), AccountsPath)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page := append([]model.Account{}, matching[start:end]...)
+	writeJSON(w, http.StatusOK, model.AccountListResponse{Data: page, Links: links})
+}
+
+// accountResponse wraps an account the way the API responds with it
+func accountResponse(account model.Account) model.AccountApiResponse {
+	return model.AccountApiResponse{
+		Data:  account,
+		Links: model.Links{Self: fmt.Sprintf("%s/%s", AccountsPath, account.ID)},
+	}
+}
+
+// matchesFilter reports whether an account matches all the filter[...] query parameters
+func matchesFilter(account model.Account, query url.Values) bool {
+	filters := map[string]string{
+		"filter[bank_id]":        account.Attributes.BankID,
+		"filter[bank_id_code]":   account.Attributes.BankIDCode,
+		"filter[account_number]": account.Attributes.AccountNumber,
+		"filter[iban]":           account.Attributes.Iban,
+		"filter[country]":        account.Attributes.Country,
+	}
+
+	for key, value := range filters {
+		if expected := query.Get(key); expected != "" && expected != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeAttributes applies the attributes set in a patch on top of the current ones and decodes the result into
+// merged. Attributes left unset in the patch are kept
+func mergeAttributes(current interface{}, patch interface{}, merged interface{}) error {
+	attributes := map[string]json.RawMessage{}
+	changes := map[string]json.RawMessage{}
+
+	for _, part := range []struct {
+		attributes interface{}
+		into       *map[string]json.RawMessage
+	}{{current, &attributes}, {patch, &changes}} {
+		encoded, err := json.Marshal(part.attributes)
+
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(encoded, part.into); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range changes {
+		attributes[key] = value
+	}
+
+	encoded, err := json.Marshal(attributes)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(encoded, merged)
+}
+
+// validateAccount returns the validation failures of an account, worded like the API does
+func validateAccount(account model.Account) []string {
+	var problems []string
+	attributes := account.Attributes
+
+	if account.ID == uuid.Nil {
+		problems = append(problems, "id in body is required")
+	}
+
+	if account.OrganisationID == uuid.Nil {
+		problems = append(problems, "organisation_id in body is required")
+	}
+
+	if account.Type != "accounts" {
+		problems = append(problems, "type in body should be one of [accounts]")
+	}
+
+	if attributes.Country == "" {
+		problems = append(problems, "country in body is required")
+	} else if !countryPattern.MatchString(attributes.Country) {
+		problems = append(problems, fmt.Sprintf("country in body should match '%s'", countryPattern))
+	}
+
+	if len(attributes.Name) == 0 || len(attributes.Name) > 4 {
+		problems = append(problems, "name in body should have between 1 and 4 items")
+	}
+
+	if attributes.BaseCurrency != "" && !currencyPattern.MatchString(attributes.BaseCurrency) {
+		problems = append(problems, fmt.Sprintf("base_currency in body should match '%s'", currencyPattern))
+	}
+
+	if attributes.Bic != "" && !bicPattern.MatchString(attributes.Bic) {
+		problems = append(problems, fmt.Sprintf("bic in body should match '%s'", bicPattern))
+	}
+
+	switch attributes.AccountClassification {
+	case "", model.AccountClassificationPersonal, model.AccountClassificationBusiness:
+	default:
+		problems = append(problems, "account_classification in body should be one of [Personal Business]")
+	}
+
+	return problems
+}
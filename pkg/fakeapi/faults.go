@@ -0,0 +1,194 @@
+package fakeapi
+
+import (
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fault describes a failure injected by the server on the requests matching a route. A fault can combine
+// latency with one of the failure modes
+type Fault struct {
+	// Latency delays the response. If MaxLatency is set, the delay is random between Latency and MaxLatency
+	Latency    time.Duration
+	MaxLatency time.Duration
+	// StatusCode responds with this status instead of serving the request, e.g. 429, 500 or 503
+	StatusCode int
+	// RetryAfter is sent as the Retry-After header along with StatusCode
+	RetryAfter string
+	// Body is sent along with StatusCode. Defaults to a Form3 error body
+	Body string
+	// TruncateBody serves the request but closes the connection halfway through the body
+	TruncateBody bool
+	// MalformedJSON serves the request but replaces the body with invalid JSON
+	MalformedJSON bool
+	// ResetConnection resets the connection without responding
+	ResetConnection bool
+	// Times is the number of requests the fault applies to. Zero applies it to every request
+	Times int
+}
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+}
+
+// A fault waiting to be applied to a route
+type scriptedFault struct {
+	method    string
+	path      string
+	fault     Fault
+	remaining int
+}
+
+// InjectFault scripts a fault for the requests with the given method and a path starting with path. An empty
+// method matches any method. Faults are applied in the order they were injected, so bursts can be scripted
+// by injecting several faults with a number of Times
+//
+//	server.InjectFault(http.MethodGet, fakeapi.AccountsPath, fakeapi.Fault{StatusCode: 503, Times: 2})
+func (s *Server) InjectFault(method string, path string, fault Fault) {
+	s.faultsMu.Lock()
+	defer s.faultsMu.Unlock()
+
+	s.faults = append(s.faults, &scriptedFault{
+		method:    method,
+		path:      path,
+		fault:     fault,
+		remaining: fault.Times,
+	})
+}
+
+// ClearFaults removes all the scripted faults
+func (s *Server) ClearFaults() {
+	s.faultsMu.Lock()
+	defer s.faultsMu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns the requests received by the server, including the ones that failed with a fault
+func (s *Server) Requests() []Request {
+	s.faultsMu.Lock()
+	defer s.faultsMu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// nextFault records the request and returns the fault to apply to it, if any
+func (s *Server) nextFault(r *http.Request) *Fault {
+	s.faultsMu.Lock()
+	defer s.faultsMu.Unlock()
+
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path})
+
+	for i, scripted := range s.faults {
+		if (scripted.method != "" && scripted.method != r.Method) || !strings.HasPrefix(r.URL.Path, scripted.path) {
+			continue
+		}
+
+		fault := scripted.fault
+
+		if scripted.remaining > 0 {
+			scripted.remaining--
+
+			if scripted.remaining == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+
+		return &fault
+	}
+
+	return nil
+}
+
+// serveWithFault applies a fault to a request. The request is only served if the fault needs the real response
+func (s *Server) serveWithFault(w http.ResponseWriter, r *http.Request, fault *Fault) {
+	if latency := fault.latency(); latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-timer.C:
+		}
+	}
+
+	switch {
+	case fault.ResetConnection:
+		resetConnection(w)
+	case fault.StatusCode != 0:
+		if fault.RetryAfter != "" {
+			w.Header().Set("Retry-After", fault.RetryAfter)
+		}
+
+		if fault.Body != "" {
+			w.WriteHeader(fault.StatusCode)
+			w.Write([]byte(fault.Body))
+			return
+		}
+
+		writeError(w, fault.StatusCode, http.StatusText(fault.StatusCode))
+	case fault.TruncateBody || fault.MalformedJSON:
+		recorder := httptest.NewRecorder()
+		s.route(recorder, r)
+		body := recorder.Body.Bytes()
+
+		for key, values := range recorder.Header() {
+			w.Header()[key] = values
+		}
+
+		if fault.MalformedJSON {
+			body = []byte(`{"data": {"attributes": [}`)
+		}
+
+		// Announcing the full length and writing less makes the server close the connection
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(recorder.Code)
+
+		if fault.TruncateBody {
+			body = body[:len(body)/2]
+		}
+
+		w.Write(body)
+	default:
+		s.route(w, r)
+	}
+}
+
+// latency returns the delay to apply
+func (f *Fault) latency() time.Duration {
+	if f.MaxLatency <= f.Latency {
+		return f.Latency
+	}
+
+	return f.Latency + time.Duration(rand.Int63n(int64(f.MaxLatency-f.Latency)))
+}
+
+// resetConnection closes the underlying TCP connection without a response, so the client sees a reset
+func resetConnection(w http.ResponseWriter) {
+	hijacker, ok := w.(http.Hijacker)
+
+	if !ok {
+		panic("fakeapi: the response writer does not support hijacking")
+	}
+
+	conn, _, err := hijacker.Hijack()
+
+	if err != nil {
+		panic(err)
+	}
+
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		// A zero linger makes Close send a RST instead of a FIN
+		tcpConn.SetLinger(0)
+	}
+
+	conn.Close()
+}
@@ -0,0 +1,161 @@
+package fakeapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/fakeapi"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/factory"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Builds an accounts service against the fake server with a fast retry policy
+func newAccountsService(server *fakeapi.Server) accounts.Form3Accounts {
+	libFactory := factory.NewForm3LibFactory()
+	httpClient := libFactory.BuildForm3Client(server.BaseURL(), client.WithRetryPolicy(client.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	}))
+
+	return libFactory.BuildAccountsService(httpClient)
+}
+
+func TestServer_InjectFault(t *testing.T) {
+
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	accountID := uuid.New()
+	server.PutAccount(testUtils.GetAccountApiResponse(accountID).Data)
+	accountPath := fakeapi.AccountsPath + "/" + accountID.String()
+
+	t.Run("should recover from a burst of server errors", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodGet, fakeapi.AccountsPath, fakeapi.Fault{StatusCode: http.StatusInternalServerError, Times: 1})
+		server.InjectFault(http.MethodGet, fakeapi.AccountsPath, fakeapi.Fault{StatusCode: http.StatusServiceUnavailable, Times: 1})
+		before := len(server.Requests())
+
+		response, err := newAccountsService(server).Fetch(accountID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, accountID, response.Data.ID)
+		assert.Len(t, server.Requests()[before:], 3)
+	})
+
+	t.Run("should return the server error when the burst outlasts the retries", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodGet, accountPath, fakeapi.Fault{StatusCode: http.StatusServiceUnavailable, Times: 5})
+
+		_, err := newAccountsService(server).Fetch(accountID)
+
+		assert.True(t, client.IsServerError(err))
+	})
+
+	t.Run("should respond with Retry-After on 429", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault("", accountPath, fakeapi.Fault{StatusCode: http.StatusTooManyRequests, RetryAfter: "0", Times: 1})
+
+		statusCode, _ := doRequest(t, server, http.MethodGet, accountPath, nil)
+		assert.Equal(t, http.StatusTooManyRequests, statusCode)
+
+		_, err := newAccountsService(server).Fetch(accountID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should not retry a create on a server error", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodPost, fakeapi.AccountsPath, fakeapi.Fault{StatusCode: http.StatusServiceUnavailable})
+		before := len(server.Requests())
+
+		_, err := newAccountsService(server).Create(testUtils.GetAccountCreateRequest(uuid.New()))
+
+		assert.True(t, client.IsServerError(err))
+		assert.Len(t, server.Requests()[before:], 1)
+	})
+
+	t.Run("should return the account when the response of its create was lost", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodPost, fakeapi.AccountsPath, fakeapi.Fault{TruncateBody: true, Times: 1})
+		newAccountID := uuid.New()
+		accountsService := newAccountsService(server)
+
+		_, err := accountsService.Create(testUtils.GetAccountCreateRequest(newAccountID))
+		require.Error(t, err)
+
+		response, err := accountsService.Create(testUtils.GetAccountCreateRequest(newAccountID))
+
+		assert.Nil(t, err)
+		assert.Equal(t, newAccountID, response.Data.ID)
+	})
+
+	t.Run("should retry a connection reset", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodGet, accountPath, fakeapi.Fault{ResetConnection: true, Times: 1})
+
+		response, err := newAccountsService(server).Fetch(accountID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, accountID, response.Data.ID)
+	})
+
+	t.Run("should return an error on a truncated body", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodGet, accountPath, fakeapi.Fault{TruncateBody: true})
+
+		response, err := newAccountsService(server).Fetch(accountID)
+
+		assert.Nil(t, response)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should return an error on malformed JSON", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodGet, accountPath, fakeapi.Fault{MalformedJSON: true})
+
+		response, err := newAccountsService(server).Fetch(accountID)
+
+		var syntaxErr *json.SyntaxError
+		assert.Nil(t, response)
+		assert.True(t, errors.As(err, &syntaxErr))
+	})
+
+	t.Run("should delay the response", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodGet, accountPath, fakeapi.Fault{Latency: 20 * time.Millisecond, MaxLatency: 30 * time.Millisecond})
+
+		start := time.Now()
+		_, err := newAccountsService(server).Fetch(accountID)
+
+		assert.Nil(t, err)
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(20*time.Millisecond))
+	})
+
+	t.Run("should time out on latency longer than the deadline", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodGet, accountPath, fakeapi.Fault{Latency: time.Second})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := newAccountsService(server).FetchWithContext(ctx, accountID)
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("should only apply a fault to the matching method", func(t *testing.T) {
+		defer server.ClearFaults()
+		server.InjectFault(http.MethodDelete, fakeapi.AccountsPath, fakeapi.Fault{StatusCode: http.StatusInternalServerError})
+
+		statusCode, _ := doRequest(t, server, http.MethodGet, accountPath, nil)
+		require.Equal(t, http.StatusOK, statusCode)
+	})
+}
@@ -0,0 +1,266 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// organisationStore keeps the organisation units in memory, in the order they were created
+type organisationStore struct {
+	byID  map[uuid.UUID]model.Organisation
+	order []uuid.UUID
+}
+
+func newOrganisationStore() *organisationStore {
+	return &organisationStore{byID: map[uuid.UUID]model.Organisation{}}
+}
+
+// all returns the organisation units in creation order
+func (st *organisationStore) all() []model.Organisation {
+	organisations := make([]model.Organisation, 0, len(st.order))
+
+	for _, id := range st.order {
+		organisations = append(organisations, st.byID[id])
+	}
+
+	return organisations
+}
+
+func (st *organisationStore) put(organisation model.Organisation) {
+	if _, ok := st.byID[organisation.ID]; !ok {
+		st.order = append(st.order, organisation.ID)
+	}
+
+	st.byID[organisation.ID] = organisation
+}
+
+func (st *organisationStore) remove(id uuid.UUID) {
+	delete(st.byID, id)
+
+	for i, storedID := range st.order {
+		if storedID == id {
+			st.order = append(st.order[:i], st.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Organisations returns a snapshot of the stored organisation units in creation order
+func (s *Server) Organisations() []model.Organisation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.organisations.all()
+}
+
+// PutOrganisation stores an organisation unit as is, bypassing validation. Used to seed the server in tests
+func (s *Server) PutOrganisation(organisation model.Organisation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.organisations.put(organisation)
+}
+
+// handleOrganisations serves the organisation units collection
+func (s *Server) handleOrganisations(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.createOrganisation(w, r)
+	case http.MethodGet:
+		s.listOrganisations(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
+
+// handleOrganisation serves a single organisation unit
+func (s *Server) handleOrganisation(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, err := uuid.Parse(rawID)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "id is not a valid uuid")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.fetchOrganisation(w, id)
+	case http.MethodPatch:
+		s.updateOrganisation(w, r, id)
+	case http.MethodDelete:
+		s.deleteOrganisation(w, r, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+	}
+}
+
+func (s *Server) createOrganisation(w http.ResponseWriter, r *http.Request) {
+	var request model.OrganisationCreateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	organisation := request.Data
+
+	if problems := validateOrganisation(organisation); len(problems) > 0 {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+strings.Join(problems, "\n"))
+		return
+	}
+
+	if _, ok := s.organisations.byID[organisation.ID]; ok {
+		writeError(w, http.StatusConflict, "Organisation unit cannot be created as it violates a duplicate constraint")
+		return
+	}
+
+	organisation.Version = 0
+	organisation.CreatedOn = s.timestamp()
+	organisation.ModifiedOn = organisation.CreatedOn
+	s.organisations.put(organisation)
+
+	writeJSON(w, http.StatusCreated, organisationResponse(organisation))
+}
+
+func (s *Server) fetchOrganisation(w http.ResponseWriter, id uuid.UUID) {
+	organisation, ok := s.organisations.byID[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, organisationResponse(organisation))
+}
+
+func (s *Server) updateOrganisation(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	var request model.OrganisationUpdateRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	if request.Data.ID != id {
+		writeError(w, http.StatusBadRequest, "id in body does not match the id in the path")
+		return
+	}
+
+	organisation, ok := s.organisations.byID[id]
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("record %s does not exist", id))
+		return
+	}
+
+	if request.Data.Version != organisation.Version {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	updated := organisation
+	updated.Attributes = model.OrganisationAttributes{}
+
+	if err := mergeAttributes(organisation.Attributes, request.Data.Attributes, &updated.Attributes); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid attributes: %v", err))
+		return
+	}
+
+	if problems := validateOrganisation(updated); len(problems) > 0 {
+		writeError(w, http.StatusBadRequest, "validation failure list:\n"+strings.Join(problems, "\n"))
+		return
+	}
+
+	updated.Version++
+	updated.ModifiedOn = s.timestamp()
+	s.organisations.put(updated)
+
+	writeJSON(w, http.StatusOK, organisationResponse(updated))
+}
+
+func (s *Server) deleteOrganisation(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	version, err := strconv.Atoi(r.URL.Query().Get("version"))
+
+	if err != nil || version < 0 {
+		writeError(w, http.StatusBadRequest, "invalid version number")
+		return
+	}
+
+	organisation, ok := s.organisations.byID[id]
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if organisation.Version != model.Version(version) {
+		writeError(w, http.StatusConflict, "invalid version")
+		return
+	}
+
+	s.organisations.remove(id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listOrganisations serves a page of organisation units, only the ones of filter[organisation_id] if set
+func (s *Server) listOrganisations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var matching []model.Organisation
+
+	for _, organisation := range s.organisations.all() {
+		if parent := query.Get("filter[organisation_id]"); parent == "" || parent == organisation.OrganisationID.String() {
+			matching = append(matching, organisation)
+		}
+	}
+
+	start, end, links, err := paginate(query, len(matching), OrganisationsPath)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page := append([]model.Organisation{}, matching[start:end]...)
+	writeJSON(w, http.StatusOK, model.OrganisationListResponse{Data: page, Links: links})
+}
+
+// organisationResponse wraps an organisation unit the way the API responds with it
+func organisationResponse(organisation model.Organisation) model.OrganisationApiResponse {
+	return model.OrganisationApiResponse{
+		Data:  organisation,
+		Links: model.Links{Self: fmt.Sprintf("%s/%s", OrganisationsPath, organisation.ID)},
+	}
+}
+
+// validateOrganisation returns the validation failures of an organisation unit, worded like the API does
+func validateOrganisation(organisation model.Organisation) []string {
+	var problems []string
+	attributes := organisation.Attributes
+
+	if organisation.ID == uuid.Nil {
+		problems = append(problems, "id in body is required")
+	}
+
+	if organisation.OrganisationID == uuid.Nil {
+		problems = append(problems, "organisation_id in body is required")
+	}
+
+	if organisation.Type != "organisations" {
+		problems = append(problems, "type in body should be one of [organisations]")
+	}
+
+	if strings.TrimSpace(attributes.Name) == "" {
+		problems = append(problems, "name in body is required")
+	}
+
+	if attributes.Country != "" && !countryPattern.MatchString(attributes.Country) {
+		problems = append(problems, fmt.Sprintf("country in body should match '%s'", countryPattern))
+	}
+
+	return problems
+}
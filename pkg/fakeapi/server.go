@@ -0,0 +1,207 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AccountsPath is the path the accounts resource is served on
+	AccountsPath = "/v1/organisation/accounts"
+	// OrganisationsPath is the path the organisation units resource is served on
+	OrganisationsPath = "/v1/organisation/units"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// Server is an in-process fake of Form3's API backed by in-memory storage. It is meant to be used in tests
+// instead of the form3tech/interview-accountapi container
+//
+//	server := fakeapi.NewServer()
+//	defer server.Close()
+//	f3 := form3.New(server.BaseURL())
+type Server struct {
+	*httptest.Server
+	mu            sync.Mutex
+	accounts      *accountStore
+	organisations *organisationStore
+	now           func() time.Time
+	faultsMu      sync.Mutex
+	faults        []*scriptedFault
+	requests      []Request
+}
+
+// NewServer creates and starts a fake API server
+func NewServer() *Server {
+	s := &Server{
+		accounts:      newAccountStore(),
+		organisations: newOrganisationStore(),
+		now:           time.Now,
+	}
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// BaseURL returns the URL of the server with a trailing slash, as expected by form3.New
+func (s *Server) BaseURL() *url.URL {
+	u, err := url.Parse(s.URL + "/")
+
+	if err != nil {
+		panic(fmt.Sprintf("fakeapi: invalid server URL: %v", err))
+	}
+
+	return u
+}
+
+// Reset removes all the stored resources
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts = newAccountStore()
+	s.organisations = newOrganisationStore()
+}
+
+// ServeHTTP applies the scripted faults and routes a request to the resource handlers
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if fault := s.nextFault(r); fault != nil {
+		s.serveWithFault(w, r, fault)
+		return
+	}
+
+	s.route(w, r)
+}
+
+// route serves a request with the resource handlers
+func (s *Server) route(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+
+	switch {
+	case path == AccountsPath:
+		s.handleAccounts(w, r)
+	case strings.HasPrefix(path, AccountsPath+"/"):
+		s.handleAccount(w, r, strings.TrimPrefix(path, AccountsPath+"/"))
+	case path == OrganisationsPath:
+		s.handleOrganisations(w, r)
+	case strings.HasPrefix(path, OrganisationsPath+"/"):
+		s.handleOrganisation(w, r, strings.TrimPrefix(path, OrganisationsPath+"/"))
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no route for %s", r.URL.Path))
+	}
+}
+
+// paginate returns the [start, end) range of the page the page[number] and page[size] parameters of a list
+// request select among count resources, and the links to the other pages
+func paginate(query url.Values, count int, path string) (int, int, model.Links, error) {
+	pageSize := defaultPageSize
+
+	if rawSize := query.Get("page[size]"); rawSize != "" {
+		size, err := strconv.Atoi(rawSize)
+
+		if err != nil || size < 1 || size > maxPageSize {
+			return 0, 0, model.Links{}, errors.New("invalid page size")
+		}
+
+		pageSize = size
+	}
+
+	lastPage := 0
+
+	if count > 0 {
+		lastPage = (count - 1) / pageSize
+	}
+
+	pageNumber := 0
+
+	switch rawNumber := query.Get("page[number]"); rawNumber {
+	case "", "first":
+	case "last":
+		pageNumber = lastPage
+	default:
+		number, err := strconv.Atoi(rawNumber)
+
+		if err != nil || number < 0 {
+			return 0, 0, model.Links{}, errors.New("invalid page number")
+		}
+
+		pageNumber = number
+	}
+
+	start := pageNumber * pageSize
+	end := start + pageSize
+
+	if start > count {
+		start = count
+	}
+
+	if end > count {
+		end = count
+	}
+
+	pageLink := func(number string) string {
+		linkQuery := url.Values{}
+
+		for key, values := range query {
+			linkQuery[key] = values
+		}
+
+		linkQuery.Set("page[number]", number)
+		linkQuery.Set("page[size]", strconv.Itoa(pageSize))
+
+		return path + "?" + linkQuery.Encode()
+	}
+
+	links := model.Links{
+		Self:  pageLink(strconv.Itoa(pageNumber)),
+		First: pageLink("first"),
+		Last:  pageLink("last"),
+	}
+
+	if pageNumber < lastPage {
+		links.Next = pageLink(strconv.Itoa(pageNumber + 1))
+	}
+
+	if pageNumber > 0 {
+		links.Prev = pageLink(strconv.Itoa(pageNumber - 1))
+	}
+
+	return start, end, links, nil
+}
+
+// timestamp returns the current time in the format used by the API
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+// Form3 error response body
+type errorBody struct {
+	ErrorMessage string `json:"error_message"`
+}
+
+// writeJSON writes a JSON:API response
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error response the way the API does
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, errorBody{ErrorMessage: message})
+}
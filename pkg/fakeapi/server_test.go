@@ -0,0 +1,266 @@
+package fakeapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/fakeapi"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// Does a request against the fake server and returns the status code and the body
+func doRequest(t *testing.T, server *fakeapi.Server, method string, path string, body interface{}) (int, []byte) {
+	var reqBody []byte
+
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewReader(reqBody))
+	require.NoError(t, err)
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	resBody, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return res.StatusCode, resBody
+}
+
+func TestServer_Accounts(t *testing.T) {
+
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	t.Run("should create and fetch an account", func(t *testing.T) {
+		accountID := uuid.New()
+
+		statusCode, body := doRequest(t, server, http.MethodPost, fakeapi.AccountsPath, testUtils.GetAccountCreateRequest(accountID))
+		require.Equal(t, http.StatusCreated, statusCode)
+
+		var created model.AccountApiResponse
+		require.NoError(t, json.Unmarshal(body, &created))
+		assert.Equal(t, accountID, created.Data.ID)
+		assert.Equal(t, model.Version(0), created.Data.Version)
+		assert.NotEmpty(t, created.Data.CreatedOn)
+		assert.Equal(t, fakeapi.AccountsPath+"/"+accountID.String(), created.Links.Self)
+
+		statusCode, body = doRequest(t, server, http.MethodGet, fakeapi.AccountsPath+"/"+accountID.String(), nil)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var fetched model.AccountApiResponse
+		require.NoError(t, json.Unmarshal(body, &fetched))
+		assert.Equal(t, created, fetched)
+	})
+
+	t.Run("should reject an invalid account", func(t *testing.T) {
+		accountToCreate := testUtils.GetAccountCreateRequest(uuid.New())
+		accountToCreate.Data.Attributes.Country = ""
+		accountToCreate.Data.Attributes.Bic = "invalid"
+
+		statusCode, body := doRequest(t, server, http.MethodPost, fakeapi.AccountsPath, accountToCreate)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Contains(t, string(body), "country in body is required")
+		assert.Contains(t, string(body), "bic in body should match")
+	})
+
+	t.Run("should reject a duplicate account", func(t *testing.T) {
+		accountToCreate := testUtils.GetAccountCreateRequest(uuid.New())
+
+		statusCode, _ := doRequest(t, server, http.MethodPost, fakeapi.AccountsPath, accountToCreate)
+		require.Equal(t, http.StatusCreated, statusCode)
+
+		statusCode, body := doRequest(t, server, http.MethodPost, fakeapi.AccountsPath, accountToCreate)
+		assert.Equal(t, http.StatusConflict, statusCode)
+		assert.Contains(t, string(body), "duplicate constraint")
+	})
+
+	t.Run("should return not found for an unknown account", func(t *testing.T) {
+		accountID := uuid.New()
+
+		statusCode, body := doRequest(t, server, http.MethodGet, fakeapi.AccountsPath+"/"+accountID.String(), nil)
+		assert.Equal(t, http.StatusNotFound, statusCode)
+		assert.JSONEq(t, `{"error_message":"record `+accountID.String()+` does not exist"}`, string(body))
+
+		statusCode, _ = doRequest(t, server, http.MethodDelete, fakeapi.AccountsPath+"/"+accountID.String()+"?version=0", nil)
+		assert.Equal(t, http.StatusNotFound, statusCode)
+	})
+
+	t.Run("should check the version on patch and delete", func(t *testing.T) {
+		accountID := uuid.New()
+		accountPath := fakeapi.AccountsPath + "/" + accountID.String()
+		statusCode, _ := doRequest(t, server, http.MethodPost, fakeapi.AccountsPath, testUtils.GetAccountCreateRequest(accountID))
+		require.Equal(t, http.StatusCreated, statusCode)
+
+		patch := model.AccountUpdateRequest{Data: model.Account{
+			ID:         accountID,
+			Type:       "accounts",
+			Version:    0,
+			Attributes: model.AccountAttributes{Name: []string{"Samantha Holder-Smith"}},
+		}}
+		statusCode, body := doRequest(t, server, http.MethodPatch, accountPath, patch)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var updated model.AccountApiResponse
+		require.NoError(t, json.Unmarshal(body, &updated))
+		assert.Equal(t, model.Version(1), updated.Data.Version)
+		assert.Equal(t, []string{"Samantha Holder-Smith"}, updated.Data.Attributes.Name)
+		// Attributes left out of the patch are kept
+		assert.Equal(t, "GB", updated.Data.Attributes.Country)
+
+		statusCode, _ = doRequest(t, server, http.MethodPatch, accountPath, patch)
+		assert.Equal(t, http.StatusConflict, statusCode)
+
+		statusCode, _ = doRequest(t, server, http.MethodDelete, accountPath, nil)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+
+		statusCode, _ = doRequest(t, server, http.MethodDelete, accountPath+"?version=0", nil)
+		assert.Equal(t, http.StatusConflict, statusCode)
+
+		statusCode, _ = doRequest(t, server, http.MethodDelete, accountPath+"?version=1", nil)
+		assert.Equal(t, http.StatusNoContent, statusCode)
+	})
+}
+
+func TestServer_ListAccounts(t *testing.T) {
+
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	for i := 0; i < 5; i++ {
+		account := testUtils.GetAccountApiResponse(uuid.New()).Data
+
+		if i%2 == 0 {
+			account.Attributes.Country = "FR"
+		}
+
+		server.PutAccount(account)
+	}
+
+	t.Run("should paginate and link the pages", func(t *testing.T) {
+		statusCode, body := doRequest(t, server, http.MethodGet, fakeapi.AccountsPath+"?page[number]=1&page[size]=2", nil)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var page model.AccountListResponse
+		require.NoError(t, json.Unmarshal(body, &page))
+
+		assert.Equal(t, []model.Account{server.Accounts()[2], server.Accounts()[3]}, page.Data)
+		assert.Equal(t, fakeapi.AccountsPath+"?page%5Bnumber%5D=2&page%5Bsize%5D=2", page.Links.Next)
+		assert.Equal(t, fakeapi.AccountsPath+"?page%5Bnumber%5D=0&page%5Bsize%5D=2", page.Links.Prev)
+		assert.Equal(t, fakeapi.AccountsPath+"?page%5Bnumber%5D=last&page%5Bsize%5D=2", page.Links.Last)
+	})
+
+	t.Run("should not link a next page from the last one", func(t *testing.T) {
+		statusCode, body := doRequest(t, server, http.MethodGet, fakeapi.AccountsPath+"?page[number]=last&page[size]=2", nil)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var page model.AccountListResponse
+		require.NoError(t, json.Unmarshal(body, &page))
+
+		assert.Len(t, page.Data, 1)
+		assert.Empty(t, page.Links.Next)
+	})
+
+	t.Run("should filter the accounts", func(t *testing.T) {
+		statusCode, body := doRequest(t, server, http.MethodGet, fakeapi.AccountsPath+"?filter[country]=FR", nil)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var page model.AccountListResponse
+		require.NoError(t, json.Unmarshal(body, &page))
+
+		assert.Len(t, page.Data, 3)
+
+		for _, account := range page.Data {
+			assert.Equal(t, "FR", account.Attributes.Country)
+		}
+	})
+}
+
+func TestServer_Organisations(t *testing.T) {
+
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	t.Run("should create, update and delete an organisation unit", func(t *testing.T) {
+		organisationID := uuid.New()
+		organisationPath := fakeapi.OrganisationsPath + "/" + organisationID.String()
+
+		statusCode, body := doRequest(t, server, http.MethodPost, fakeapi.OrganisationsPath, testUtils.GetOrganisationCreateRequest(organisationID))
+		require.Equal(t, http.StatusCreated, statusCode)
+
+		var created model.OrganisationApiResponse
+		require.NoError(t, json.Unmarshal(body, &created))
+		assert.Equal(t, organisationID, created.Data.ID)
+		assert.NotEmpty(t, created.Data.CreatedOn)
+		assert.Equal(t, organisationPath, created.Links.Self)
+
+		statusCode, _ = doRequest(t, server, http.MethodPost, fakeapi.OrganisationsPath, testUtils.GetOrganisationCreateRequest(organisationID))
+		assert.Equal(t, http.StatusConflict, statusCode)
+
+		patch := model.OrganisationUpdateRequest{Data: model.Organisation{
+			ID:         organisationID,
+			Type:       "organisations",
+			Attributes: model.OrganisationAttributes{Name: "Holder Holdings Ltd"},
+		}}
+		statusCode, body = doRequest(t, server, http.MethodPatch, organisationPath, patch)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var updated model.OrganisationApiResponse
+		require.NoError(t, json.Unmarshal(body, &updated))
+		assert.Equal(t, model.Version(1), updated.Data.Version)
+		assert.Equal(t, "Holder Holdings Ltd", updated.Data.Attributes.Name)
+		// Attributes left out of the patch are kept
+		assert.Equal(t, "London", updated.Data.Attributes.City)
+
+		statusCode, _ = doRequest(t, server, http.MethodDelete, organisationPath+"?version=0", nil)
+		assert.Equal(t, http.StatusConflict, statusCode)
+
+		statusCode, _ = doRequest(t, server, http.MethodDelete, organisationPath+"?version=1", nil)
+		assert.Equal(t, http.StatusNoContent, statusCode)
+		assert.Empty(t, server.Organisations())
+	})
+
+	t.Run("should reject an invalid organisation unit", func(t *testing.T) {
+		organisationToCreate := testUtils.GetOrganisationCreateRequest(uuid.New())
+		organisationToCreate.Data.Attributes.Name = ""
+
+		statusCode, body := doRequest(t, server, http.MethodPost, fakeapi.OrganisationsPath, organisationToCreate)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Contains(t, string(body), "name in body is required")
+	})
+
+	t.Run("should list the organisation units of a parent", func(t *testing.T) {
+		parentID := uuid.New()
+
+		for i := 0; i < 3; i++ {
+			organisation := testUtils.GetOrganisationApiResponse(uuid.New()).Data
+
+			if i > 0 {
+				organisation.OrganisationID = parentID
+			}
+
+			server.PutOrganisation(organisation)
+		}
+
+		statusCode, body := doRequest(t, server, http.MethodGet, fakeapi.OrganisationsPath+"?filter[organisation_id]="+parentID.String()+"&page[size]=1", nil)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var page model.OrganisationListResponse
+		require.NoError(t, json.Unmarshal(body, &page))
+
+		assert.Equal(t, []model.Organisation{server.Organisations()[1]}, page.Data)
+		assert.NotEmpty(t, page.Links.Next)
+	})
+}
@@ -0,0 +1,242 @@
+package form3
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/factory"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultUserAgent is the User-Agent sent when none is configured
+const DefaultUserAgent = "accounts-api-client"
+
+// Config holds everything the lib can be tailored with. Use DefaultConfig as a starting point
+type Config struct {
+	// BaseURL is the URL of the API, e.g. http://localhost:8080/
+	BaseURL *url.URL
+	// HTTPClient does the requests. When nil, a http.Client with Timeout is used
+	HTTPClient client.HTTPClient
+	// Timeout of every request. Only applies to the default HTTP client, a custom one carries its own
+	Timeout time.Duration
+	// UserAgent is sent with every request
+	UserAgent string
+	// DefaultHeaders are sent with every request
+	DefaultHeaders http.Header
+	// APIVersion is the version prefix of the resource endpoints, e.g. v1
+	APIVersion string
+	// RetryPolicy is used to retry requests that failed with a transient error
+	RetryPolicy client.RetryPolicy
+	// Logger receives the log entries of the client. When nil, nothing is logged
+	Logger client.Logger
+	// Metrics receives the measurements of every request, e.g. a client.PrometheusRecorder. When nil, nothing is
+	// measured
+	Metrics client.MetricsRecorder
+	// Tracer records a span for every operation and request, e.g. one built with tracing.NewTracer. When nil,
+	// nothing is traced but a span context in the context of an operation is still propagated
+	Tracer tracing.Tracer
+	// LogRequests logs every attempt of a request to the Logger
+	LogRequests bool
+	// LogBodies logs the request and response bodies too, masked by the client.DefaultRedactedFields
+	LogBodies bool
+	// OrganisationID is set on the resources created without an organisation ID
+	OrganisationID uuid.UUID
+	// NotFoundAsDeleted makes the delete of a resource that doesn't exist succeed, e.g. when an earlier attempt
+	// already deleted it
+	NotFoundAsDeleted bool
+	// ValidateAccounts checks the accounts before they are created, so an invalid account fails without a round
+	// trip to the API
+	ValidateAccounts bool
+	// Credentials are the client credentials issued by Form3
+	Credentials Credentials
+	// Signer signs every request, e.g. a client.HTTPSigner. When nil, requests are not signed
+	Signer client.Signer
+	// Interceptors wrap every request, the first one is the outermost
+	Interceptors []client.Interceptor
+}
+
+// Credentials holds the secrets used to authenticate with the API. When a client ID is set, every request
+// carries a bearer token obtained with the OAuth2 client credentials grant
+type Credentials struct {
+	ClientID     string
+	ClientSecret string
+	// TokenURL is the token endpoint. When nil, it is the oauth2/token endpoint of the API version
+	TokenURL *url.URL
+}
+
+// DefaultConfig returns the configuration used by New before any option is applied
+func DefaultConfig() Config {
+	return Config{
+		Timeout:     30 * time.Second,
+		UserAgent:   DefaultUserAgent,
+		APIVersion:  factory.DefaultAPIVersion,
+		RetryPolicy: client.NoRetryPolicy(),
+	}
+}
+
+// Validate checks the configuration and returns all the problems found in a single error
+func (c Config) Validate() error {
+	var problems []string
+
+	if c.BaseURL == nil {
+		problems = append(problems, "base URL is required")
+	} else if !c.BaseURL.IsAbs() || c.BaseURL.Host == "" {
+		problems = append(problems, fmt.Sprintf("base URL %q must be absolute", c.BaseURL))
+	}
+
+	if c.Timeout < 0 {
+		problems = append(problems, "timeout must not be negative")
+	}
+
+	if strings.Trim(c.APIVersion, "/") == "" {
+		problems = append(problems, "API version is required")
+	}
+
+	if c.RetryPolicy.MaxAttempts < 0 || c.RetryPolicy.BaseDelay < 0 || c.RetryPolicy.MaxDelay < 0 {
+		problems = append(problems, "retry policy values must not be negative")
+	}
+
+	if c.RetryPolicy.Jitter < 0 || c.RetryPolicy.Jitter > 1 {
+		problems = append(problems, "retry policy jitter must be between 0 and 1")
+	}
+
+	if (c.Credentials.ClientID == "") != (c.Credentials.ClientSecret == "") {
+		problems = append(problems, "client ID and client secret must be set together")
+	}
+
+	if c.Credentials.TokenURL != nil && (!c.Credentials.TokenURL.IsAbs() || c.Credentials.TokenURL.Host == "") {
+		problems = append(problems, fmt.Sprintf("token URL %q must be absolute", c.Credentials.TokenURL))
+	}
+
+	if len(problems) > 0 {
+		return errors.New("form3: invalid config: " + strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// Option changes the configuration used by New
+type Option func(c *Config)
+
+// WithHTTPClient sets the HTTP client that does the requests
+func WithHTTPClient(httpClient client.HTTPClient) Option {
+	return func(c *Config) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout of every request of the default HTTP client
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.Timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Config) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithHeader adds a header sent with every request
+func WithHeader(key string, value string) Option {
+	return func(c *Config) {
+		if c.DefaultHeaders == nil {
+			c.DefaultHeaders = http.Header{}
+		}
+
+		c.DefaultHeaders.Add(key, value)
+	}
+}
+
+// WithAPIVersion sets the version prefix of the resource endpoints
+func WithAPIVersion(apiVersion string) Option {
+	return func(c *Config) {
+		c.APIVersion = apiVersion
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry requests that failed with a transient error
+func WithRetryPolicy(policy client.RetryPolicy) Option {
+	return func(c *Config) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithLogger sets the logger the client reports to
+func WithLogger(logger client.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithRequestLogging logs every attempt of a request to the logger. With logBodies, the bodies are logged too,
+// with their personal data masked
+func WithRequestLogging(logBodies bool) Option {
+	return func(c *Config) {
+		c.LogRequests = true
+		c.LogBodies = logBodies
+	}
+}
+
+// WithMetrics sets the recorder that receives the measurements of every request
+func WithMetrics(recorder client.MetricsRecorder) Option {
+	return func(c *Config) {
+		c.Metrics = recorder
+	}
+}
+
+// WithTracer sets the tracer that records a span for every operation and request
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(c *Config) {
+		c.Tracer = tracer
+	}
+}
+
+// WithOrganisationID sets the organisation ID of the resources created without one
+func WithOrganisationID(organisationID uuid.UUID) Option {
+	return func(c *Config) {
+		c.OrganisationID = organisationID
+	}
+}
+
+// WithNotFoundAsDeleted makes the delete of a resource that doesn't exist succeed
+func WithNotFoundAsDeleted() Option {
+	return func(c *Config) {
+		c.NotFoundAsDeleted = true
+	}
+}
+
+// WithAccountValidation checks the accounts with accounts.ValidateCreateRequest before they are created
+func WithAccountValidation() Option {
+	return func(c *Config) {
+		c.ValidateAccounts = true
+	}
+}
+
+// WithCredentials sets the secrets used to authenticate with the API
+func WithCredentials(credentials Credentials) Option {
+	return func(c *Config) {
+		c.Credentials = credentials
+	}
+}
+
+// WithSigner sets the signer that signs every request
+func WithSigner(signer client.Signer) Option {
+	return func(c *Config) {
+		c.Signer = signer
+	}
+}
+
+// WithInterceptors adds interceptors that wrap every request
+func WithInterceptors(interceptors ...client.Interceptor) Option {
+	return func(c *Config) {
+		c.Interceptors = append(c.Interceptors, interceptors...)
+	}
+}
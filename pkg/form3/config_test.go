@@ -0,0 +1,235 @@
+package form3
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/fakeapi"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// recordingHttpClient records the requests and responds with a not found
+type recordingHttpClient struct {
+	requests []*http.Request
+}
+
+func (cl *recordingHttpClient) Do(req *http.Request) (*http.Response, error) {
+	cl.requests = append(cl.requests, req)
+
+	return &http.Response{
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"error_message":"not found"}`))),
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{},
+	}, nil
+}
+
+func TestFrom3_NewWithOptions(t *testing.T) {
+
+	t.Run("should apply the options to every request", func(t *testing.T) {
+		baseURL, err := url.Parse("http://localhost:8080/api")
+		require.NoError(t, err)
+		httpClient := &recordingHttpClient{}
+		accountID := uuid.New()
+
+		f3 := New(
+			baseURL,
+			WithHTTPClient(httpClient),
+			WithUserAgent("reconciliation-job/1.0"),
+			WithHeader("X-Team", "payments"),
+			WithAPIVersion("v2"),
+		)
+
+		_, err = f3.Accounts.Fetch(accountID)
+		assert.True(t, client.IsNotFound(err))
+
+		require.Len(t, httpClient.requests, 1)
+		req := httpClient.requests[0]
+		assert.Equal(t, "http://localhost:8080/api/v2/organisation/accounts/"+accountID.String(), req.URL.String())
+		assert.Equal(t, "reconciliation-job/1.0", req.Header.Get("User-Agent"))
+		assert.Equal(t, "payments", req.Header.Get("X-Team"))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	})
+
+	t.Run("should retry with the retry policy and log the retries", func(t *testing.T) {
+		server := fakeapi.NewServer()
+		defer server.Close()
+		server.InjectFault(http.MethodGet, fakeapi.AccountsPath, fakeapi.Fault{StatusCode: http.StatusServiceUnavailable, Times: 1})
+
+		var messages []string
+		logger := client.LoggerFunc(func(ctx context.Context, msg string, fields map[string]interface{}) {
+			messages = append(messages, msg)
+		})
+
+		f3 := New(
+			server.BaseURL(),
+			WithRetryPolicy(client.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}),
+			WithLogger(logger),
+		)
+
+		_, err := f3.Accounts.List(nil)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"form3: retrying request"}, messages)
+	})
+
+	t.Run("should log the requests without personal data", func(t *testing.T) {
+		server := fakeapi.NewServer()
+		defer server.Close()
+
+		var out bytes.Buffer
+		f3 := New(
+			server.BaseURL(),
+			WithLogger(client.NewStdLogger(log.New(&out, "", 0))),
+			WithRequestLogging(true),
+		)
+
+		_, err := f3.Accounts.Create(testUtils.GetAccountCreateRequest(uuid.New()))
+		require.NoError(t, err)
+
+		assert.Contains(t, out.String(), "form3: request attempt=1")
+		assert.Contains(t, out.String(), "status=201")
+		assert.Contains(t, out.String(), `"name":"[REDACTED]"`)
+		assert.NotContains(t, out.String(), `Samantha Holder`)
+	})
+
+	t.Run("should measure the requests", func(t *testing.T) {
+		server := fakeapi.NewServer()
+		defer server.Close()
+
+		recorder := client.NewPrometheusRecorder()
+		f3 := New(server.BaseURL(), WithMetrics(recorder))
+
+		_, err := f3.Accounts.Fetch(uuid.New())
+		require.True(t, client.IsNotFound(err))
+
+		var out bytes.Buffer
+		require.NoError(t, recorder.WriteMetrics(&out))
+		assert.Contains(t, out.String(), `form3_client_requests_total{operation="fetch",resource="accounts",status_class="4xx"} 1`)
+	})
+
+	t.Run("should trace the operations and their requests", func(t *testing.T) {
+		server := fakeapi.NewServer()
+		defer server.Close()
+
+		exporter := tracing.NewInMemoryExporter()
+		f3 := New(server.BaseURL(), WithTracer(tracing.NewTracer(exporter)))
+
+		_, err := f3.Accounts.List(nil)
+		require.NoError(t, err)
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "HTTP GET", spans[0].Name)
+		assert.Equal(t, http.StatusOK, spans[0].Attributes[tracing.AttributeHTTPStatus])
+		assert.Equal(t, "accounts.list", spans[1].Name)
+		assert.Equal(t, spans[1].SpanContext.SpanID, spans[0].ParentSpanID)
+	})
+
+	t.Run("should run the interceptors", func(t *testing.T) {
+		server := fakeapi.NewServer()
+		defer server.Close()
+
+		var audited []string
+		audit := func(ctx context.Context, req *client.Request, next client.Handler) (*http.Response, error) {
+			res, err := next(ctx, req)
+
+			if err == nil {
+				audited = append(audited, fmt.Sprintf("%s %s %d", req.Method, req.Path, res.StatusCode))
+			}
+
+			return res, err
+		}
+
+		f3 := New(server.BaseURL(), WithInterceptors(audit))
+
+		_, err := f3.Accounts.List(nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"GET v1/organisation/accounts 200"}, audited)
+	})
+
+	t.Run("should authenticate with a token of the credentials", func(t *testing.T) {
+		tokenRequests := 0
+		var authorizations []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/v1/oauth2/token" {
+				tokenRequests++
+				w.Write([]byte(`{"access_token": "token", "token_type": "bearer", "expires_in": 3600}`))
+				return
+			}
+
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			w.Write([]byte(`{"data": []}`))
+		}))
+		defer server.Close()
+
+		baseURL, err := url.Parse(server.URL)
+		require.NoError(t, err)
+
+		f3 := New(baseURL, WithCredentials(Credentials{ClientID: "client", ClientSecret: "secret"}))
+
+		for i := 0; i < 2; i++ {
+			_, err = f3.Accounts.List(nil)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 1, tokenRequests)
+		assert.Equal(t, []string{"Bearer token", "Bearer token"}, authorizations)
+	})
+}
+
+func TestFrom3_NewWithConfig(t *testing.T) {
+
+	t.Run("should create the lib from a valid config", func(t *testing.T) {
+		server := fakeapi.NewServer()
+		defer server.Close()
+
+		config := DefaultConfig()
+		config.BaseURL = server.BaseURL()
+		config.Timeout = time.Second
+
+		f3, err := NewWithConfig(config)
+		require.NoError(t, err)
+
+		_, err = f3.Accounts.List(nil)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should report all the problems of an invalid config", func(t *testing.T) {
+		config := DefaultConfig()
+		config.Timeout = -time.Second
+		config.APIVersion = ""
+		config.RetryPolicy.Jitter = 2
+
+		f3, err := NewWithConfig(config)
+
+		assert.Nil(t, f3)
+		assert.EqualError(
+			t,
+			err,
+			"form3: invalid config: base URL is required; timeout must not be negative; API version is required; "+
+				"retry policy jitter must be between 0 and 1",
+		)
+	})
+
+	t.Run("should reject a relative base URL", func(t *testing.T) {
+		config := DefaultConfig()
+		config.BaseURL = &url.URL{Path: "localhost:8080"}
+
+		_, err := NewWithConfig(config)
+
+		assert.Contains(t, err.Error(), "must be absolute")
+	})
+}
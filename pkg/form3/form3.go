@@ -1,23 +1,128 @@
 package form3
 
 import (
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
 	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/factory"
 	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/organisations"
+	"net/http"
 	"net/url"
+	"strings"
 )
 
 // FormResources is a struct with all the available resources of the lib
 type FormResources struct {
-	Accounts accounts.Form3Accounts
+	Accounts      accounts.Form3Accounts
+	Organisations organisations.Form3Organisations
 }
 
-// New creates and initialises a new Form3 client lib
-func New(bu *url.URL) *FormResources {
-	libFactory := factory.NewForm3LibFactory()
-	httpClient := libFactory.BuildForm3Client(bu)
-	accountsService := libFactory.BuildAccountsService(httpClient)
+// New creates and initialises a new Form3 client lib. Options are applied on top of DefaultConfig
+func New(bu *url.URL, opts ...Option) *FormResources {
+	config := DefaultConfig()
+	config.BaseURL = bu
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return build(config)
+}
+
+// NewWithConfig validates a Config and creates a new Form3 client lib from it
+func NewWithConfig(config Config) (*FormResources, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return build(config), nil
+}
+
+// build wires the resources of the lib together
+func build(config Config) *FormResources {
+	httpClient := config.HTTPClient
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: config.Timeout}
+	}
+
+	if config.Credentials.ClientID != "" {
+		httpClient = client.NewOAuth2Client(httpClient, client.NewClientCredentialsTokenSource(
+			tokenURL(config),
+			config.Credentials.ClientID,
+			config.Credentials.ClientSecret,
+			client.WithTokenHTTPClient(httpClient),
+		))
+	}
+
+	factoryOpts := []factory.Option{
+		factory.WithHTTPClient(httpClient),
+		factory.WithAPIVersion(strings.Trim(config.APIVersion, "/")),
+		factory.WithOrganisationID(config.OrganisationID),
+		factory.WithInterceptors(config.Interceptors...),
+		factory.WithTracer(config.Tracer),
+	}
+
+	if config.NotFoundAsDeleted {
+		factoryOpts = append(factoryOpts, factory.WithNotFoundAsDeleted())
+	}
+
+	if config.ValidateAccounts {
+		factoryOpts = append(factoryOpts, factory.WithAccountValidation())
+	}
+
+	libFactory := factory.NewForm3LibFactory(factoryOpts...)
+
+	clientOpts := []client.RestClientOption{
+		client.WithRetryPolicy(config.RetryPolicy),
+		client.WithUserAgent(config.UserAgent),
+		client.WithDefaultHeaders(config.DefaultHeaders),
+	}
+
+	if config.Logger != nil {
+		clientOpts = append(clientOpts, client.WithLogger(config.Logger))
+	}
+
+	if config.Metrics != nil {
+		clientOpts = append(clientOpts, client.WithMetrics(config.Metrics))
+	}
+
+	if config.LogRequests {
+		clientOpts = append(clientOpts, client.WithRequestLogging(config.LogBodies))
+	}
+
+	if config.Signer != nil {
+		clientOpts = append(clientOpts, client.WithSigner(config.Signer))
+	}
+
+	form3Client := libFactory.BuildForm3Client(withTrailingSlash(config.BaseURL), clientOpts...)
+	accountsService := libFactory.BuildAccountsService(form3Client)
+	organisationsService := libFactory.BuildOrganisationsService(form3Client)
 
 	return &FormResources{
-		Accounts: accountsService,
+		Accounts:      accountsService,
+		Organisations: organisationsService,
+	}
+}
+
+// withTrailingSlash returns a copy of the base URL ending with a slash, as the resource paths are appended to it
+func withTrailingSlash(bu *url.URL) *url.URL {
+	if bu == nil || strings.HasSuffix(bu.Path, "/") {
+		return bu
+	}
+
+	normalised := *bu
+	normalised.Path += "/"
+
+	return &normalised
+}
+
+// tokenURL returns the token endpoint of the credentials
+func tokenURL(config Config) *url.URL {
+	if config.Credentials.TokenURL != nil {
+		return config.Credentials.TokenURL
 	}
+
+	return withTrailingSlash(config.BaseURL).ResolveReference(&url.URL{
+		Path: strings.Trim(config.APIVersion, "/") + "/oauth2/token",
+	})
 }
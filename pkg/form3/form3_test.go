@@ -0,0 +1,187 @@
+package form3
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/fakeapi"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/organisations"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFrom3_NewWithFakeAPI(t *testing.T) {
+
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	t.Run("should do all the account operations", func(t *testing.T) {
+		accountID := uuid.New()
+		accountToCreate := testUtils.GetAccountCreateRequest(accountID)
+
+		f3 := New(server.BaseURL())
+
+		accountApiResponse, err := f3.Accounts.Create(accountToCreate)
+		require.Nil(t, err)
+		assert.Equal(t, accountToCreate.Data.ID, accountApiResponse.Data.ID)
+		assert.Equal(t, accountToCreate.Data.Attributes.Country, accountApiResponse.Data.Attributes.Country)
+
+		fetchResponse, err := f3.Accounts.Fetch(accountID)
+		require.Nil(t, err)
+		assert.Equal(t, accountApiResponse, fetchResponse)
+
+		listResponse, err := f3.Accounts.List(&accounts.ListOptions{Filter: &accounts.Filter{BankID: "400300"}})
+		require.Nil(t, err)
+		assert.Equal(t, []model.Account{fetchResponse.Data}, listResponse.Data)
+
+		updateResponse, err := f3.Accounts.Mutate(accountID, 1, func(account *model.Account) error {
+			account.Attributes.Name = []string{"Samantha Holder-Smith"}
+			return nil
+		})
+		require.Nil(t, err)
+		assert.Equal(t, model.Version(1), updateResponse.Data.Version)
+		assert.Equal(t, []string{"Samantha Holder-Smith"}, updateResponse.Data.Attributes.Name)
+
+		err = f3.Accounts.Delete(accountID, 1)
+		assert.Nil(t, err)
+		assert.Empty(t, server.Accounts())
+	})
+
+	t.Run("should return errors", func(t *testing.T) {
+		accountID := uuid.New()
+		accountToCreate := testUtils.GetAccountCreateRequest(accountID)
+		accountToCreate.Data.Attributes.Country = ""
+		f3 := New(server.BaseURL())
+
+		_, err := f3.Accounts.Create(accountToCreate)
+		assert.True(t, client.IsValidation(err))
+
+		_, err = f3.Accounts.Fetch(accountID)
+		assert.True(t, client.IsNotFound(err))
+
+		err = f3.Accounts.Delete(accountID, 0)
+		assert.True(t, client.IsNotFound(err))
+	})
+
+	t.Run("should validate the accounts before creating them", func(t *testing.T) {
+		accountToCreate := testUtils.GetAccountCreateRequest(uuid.New())
+		accountToCreate.Data.Attributes.Country = ""
+		f3 := New(server.BaseURL(), WithAccountValidation())
+		before := len(server.Requests())
+
+		_, err := f3.Accounts.Create(accountToCreate)
+
+		assert.True(t, client.IsValidation(err))
+		assert.True(t, errors.Is(err, accounts.ErrInvalidAccount))
+		assert.Len(t, server.Requests()[before:], 0)
+	})
+
+	t.Run("should delete an account at a non-zero version", func(t *testing.T) {
+		accountID := uuid.New()
+		f3 := New(server.BaseURL())
+
+		_, err := f3.Accounts.Create(testUtils.GetAccountCreateRequest(accountID))
+		require.Nil(t, err)
+
+		for _, name := range []string{"Sam Holder", "Samantha Holder-Smith"} {
+			_, err = f3.Accounts.Mutate(accountID, 1, func(account *model.Account) error {
+				account.Attributes.Name = []string{name}
+				return nil
+			})
+			require.Nil(t, err)
+		}
+
+		err = f3.Accounts.Delete(accountID, 1)
+		assert.True(t, client.IsConflict(err))
+
+		err = f3.Accounts.Delete(accountID, 2)
+		assert.Nil(t, err)
+
+		_, err = f3.Accounts.Fetch(accountID)
+		assert.True(t, client.IsNotFound(err))
+	})
+
+	t.Run("should delete the latest version of an account", func(t *testing.T) {
+		accountID := uuid.New()
+		f3 := New(server.BaseURL(), WithNotFoundAsDeleted())
+
+		_, err := f3.Accounts.Create(testUtils.GetAccountCreateRequest(accountID))
+		require.Nil(t, err)
+
+		for i := 0; i < 10; i++ {
+			_, err = f3.Accounts.Mutate(accountID, 1, func(account *model.Account) error {
+				account.Attributes.Status = "confirmed"
+				return nil
+			})
+			require.Nil(t, err)
+		}
+
+		err = f3.Accounts.DeleteLatest(accountID, 1)
+		assert.Nil(t, err)
+
+		_, err = f3.Accounts.Fetch(accountID)
+		assert.True(t, client.IsNotFound(err))
+
+		assert.Nil(t, f3.Accounts.Delete(accountID, 10))
+		assert.Nil(t, f3.Accounts.DeleteLatest(accountID, 1))
+	})
+
+	t.Run("should walk all the pages", func(t *testing.T) {
+		server.Reset()
+
+		for i := 0; i < 7; i++ {
+			server.PutAccount(testUtils.GetAccountApiResponse(uuid.New()).Data)
+		}
+
+		f3 := New(server.BaseURL())
+		it := f3.Accounts.ListAll(&accounts.ListOptions{PageSize: 3})
+
+		var listed []model.Account
+		for it.Next() {
+			listed = append(listed, it.Account())
+		}
+
+		assert.Nil(t, it.Err())
+		assert.Equal(t, server.Accounts(), listed)
+	})
+	t.Run("should provision an organisation unit and its accounts", func(t *testing.T) {
+		server.Reset()
+		f3 := New(server.BaseURL())
+
+		var organisationResponse model.OrganisationApiResponse
+		created, err := f3.Organisations.CreateOrGet(testUtils.GetOrganisationCreateRequest(uuid.New()), &organisationResponse)
+		require.Nil(t, err)
+		assert.True(t, created)
+
+		organisationID := organisationResponse.Data.ID
+		accountToCreate := testUtils.GetAccountCreateRequest(uuid.New())
+		accountToCreate.Data.OrganisationID = organisationID
+
+		_, err = f3.Accounts.Create(accountToCreate)
+		require.Nil(t, err)
+
+		var listResponse model.OrganisationListResponse
+		listOptions := &organisations.ListOptions{ParentOrganisationID: organisationResponse.Data.OrganisationID}
+		err = f3.Organisations.List(listOptions.Query(), &listResponse)
+		require.Nil(t, err)
+		assert.Equal(t, []model.Organisation{organisationResponse.Data}, listResponse.Data)
+
+		organisationUpdate := organisationResponse.Data
+		organisationUpdate.Attributes.Name = "Holder Holdings Ltd"
+		var updateResponse model.OrganisationApiResponse
+		err = f3.Organisations.Update(&model.OrganisationUpdateRequest{Data: organisationUpdate}, &updateResponse)
+		require.Nil(t, err)
+		assert.Equal(t, model.Version(1), updateResponse.Data.Version)
+
+		err = f3.Organisations.DeleteLatest(organisationID, 1)
+		assert.Nil(t, err)
+		assert.Empty(t, server.Organisations())
+
+		err = f3.Organisations.Fetch(organisationID, &model.OrganisationApiResponse{})
+		assert.True(t, client.IsNotFound(err))
+	})
+}
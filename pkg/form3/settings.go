@@ -0,0 +1,322 @@
+package form3
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewFromEnv. FORM3_PROFILE selects the profile of NewFromFile
+const (
+	EnvBaseURL          = "FORM3_BASE_URL"
+	EnvTimeout          = "FORM3_TIMEOUT"
+	EnvUserAgent        = "FORM3_USER_AGENT"
+	EnvAPIVersion       = "FORM3_API_VERSION"
+	EnvOrganisationID   = "FORM3_ORGANISATION_ID"
+	EnvClientID         = "FORM3_CLIENT_ID"
+	EnvClientSecret     = "FORM3_CLIENT_SECRET"
+	EnvTokenURL         = "FORM3_TOKEN_URL"
+	EnvKeyID            = "FORM3_KEY_ID"
+	EnvPrivateKeyFile   = "FORM3_PRIVATE_KEY_FILE"
+	EnvRetryMaxAttempts = "FORM3_RETRY_MAX_ATTEMPTS"
+	EnvRetryBaseDelay   = "FORM3_RETRY_BASE_DELAY"
+	EnvRetryMaxDelay    = "FORM3_RETRY_MAX_DELAY"
+	EnvRetryJitter      = "FORM3_RETRY_JITTER"
+	EnvRetryPost        = "FORM3_RETRY_POST"
+	EnvProfile          = "FORM3_PROFILE"
+)
+
+// Environment variable of every setting, used in the errors of ConfigFromEnv
+var envNames = map[string]string{
+	"base_url":                     EnvBaseURL,
+	"timeout":                      EnvTimeout,
+	"organisation_id":              EnvOrganisationID,
+	"credentials.token_url":        EnvTokenURL,
+	"credentials.key_id":           EnvKeyID,
+	"credentials.private_key_file": EnvPrivateKeyFile,
+	"retry.max_attempts":           EnvRetryMaxAttempts,
+	"retry.base_delay":             EnvRetryBaseDelay,
+	"retry.max_delay":              EnvRetryMaxDelay,
+	"retry.jitter":                 EnvRetryJitter,
+	"retry.retry_post":             EnvRetryPost,
+}
+
+// settings holds the configuration as read from the environment or a file, before it is parsed
+type settings struct {
+	BaseURL        string              `yaml:"base_url"`
+	Timeout        string              `yaml:"timeout"`
+	UserAgent      string              `yaml:"user_agent"`
+	APIVersion     string              `yaml:"api_version"`
+	OrganisationID string              `yaml:"organisation_id"`
+	Credentials    credentialsSettings `yaml:"credentials"`
+	Retry          retrySettings       `yaml:"retry"`
+}
+
+type credentialsSettings struct {
+	ClientID       string `yaml:"client_id"`
+	ClientSecret   string `yaml:"client_secret"`
+	TokenURL       string `yaml:"token_url"`
+	KeyID          string `yaml:"key_id"`
+	PrivateKeyFile string `yaml:"private_key_file"`
+}
+
+type retrySettings struct {
+	MaxAttempts string `yaml:"max_attempts"`
+	BaseDelay   string `yaml:"base_delay"`
+	MaxDelay    string `yaml:"max_delay"`
+	Jitter      string `yaml:"jitter"`
+	RetryPost   string `yaml:"retry_post"`
+}
+
+// configFile is the layout of a configuration file. Settings at the top level are used when the file
+// has no profiles
+type configFile struct {
+	DefaultProfile string              `yaml:"default_profile"`
+	Profiles       map[string]settings `yaml:"profiles"`
+	settings       `yaml:",inline"`
+}
+
+// NewFromEnv creates a new Form3 client lib from the FORM3_* environment variables
+func NewFromEnv() (*FormResources, error) {
+	config, err := ConfigFromEnv()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithConfig(config)
+}
+
+// NewFromFile creates a new Form3 client lib from a YAML or JSON configuration file. The profile is selected
+// with FORM3_PROFILE, falling back to the default_profile of the file
+func NewFromFile(path string) (*FormResources, error) {
+	config, err := LoadConfigFile(path, os.Getenv(EnvProfile))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithConfig(config)
+}
+
+// ConfigFromEnv reads a Config from the FORM3_* environment variables. Unset variables keep the DefaultConfig values
+func ConfigFromEnv() (Config, error) {
+	s := settings{
+		BaseURL:        os.Getenv(EnvBaseURL),
+		Timeout:        os.Getenv(EnvTimeout),
+		UserAgent:      os.Getenv(EnvUserAgent),
+		APIVersion:     os.Getenv(EnvAPIVersion),
+		OrganisationID: os.Getenv(EnvOrganisationID),
+		Credentials: credentialsSettings{
+			ClientID:       os.Getenv(EnvClientID),
+			ClientSecret:   os.Getenv(EnvClientSecret),
+			TokenURL:       os.Getenv(EnvTokenURL),
+			KeyID:          os.Getenv(EnvKeyID),
+			PrivateKeyFile: os.Getenv(EnvPrivateKeyFile),
+		},
+		Retry: retrySettings{
+			MaxAttempts: os.Getenv(EnvRetryMaxAttempts),
+			BaseDelay:   os.Getenv(EnvRetryBaseDelay),
+			MaxDelay:    os.Getenv(EnvRetryMaxDelay),
+			Jitter:      os.Getenv(EnvRetryJitter),
+			RetryPost:   os.Getenv(EnvRetryPost),
+		},
+	}
+
+	config, err := s.config(func(field string) string {
+		return envNames[field]
+	})
+
+	if err != nil {
+		return Config{}, fmt.Errorf("form3: invalid environment: %w", err)
+	}
+
+	return config, nil
+}
+
+// LoadConfigFile reads a Config from a profile of a YAML or JSON configuration file. An empty profile selects
+// the default_profile of the file, or the only profile there is
+//
+//	default_profile: sandbox
+//	profiles:
+//	  sandbox:
+//	    base_url: http://localhost:8080/
+//	    timeout: 10s
+//	    retry:
+//	      max_attempts: 3
+func LoadConfigFile(path string, profile string) (Config, error) {
+	content, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return Config{}, fmt.Errorf("form3: reading config file: %w", err)
+	}
+
+	var file configFile
+
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return Config{}, fmt.Errorf("form3: decoding config file %s: %w", path, err)
+	}
+
+	s, profile, err := file.selectProfile(profile)
+
+	if err != nil {
+		return Config{}, fmt.Errorf("form3: config file %s: %w", path, err)
+	}
+
+	config, err := s.config(func(field string) string {
+		return field
+	})
+
+	if err != nil {
+		if profile != "" {
+			return Config{}, fmt.Errorf("form3: config file %s: profile %s: %w", path, profile, err)
+		}
+
+		return Config{}, fmt.Errorf("form3: config file %s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// selectProfile returns the settings of a profile and its name
+func (f *configFile) selectProfile(profile string) (settings, string, error) {
+	if len(f.Profiles) == 0 {
+		if profile != "" {
+			return settings{}, "", fmt.Errorf("profile %q not found, the file has no profiles", profile)
+		}
+
+		return f.settings, "", nil
+	}
+
+	if profile == "" {
+		profile = f.DefaultProfile
+	}
+
+	if profile == "" && len(f.Profiles) == 1 {
+		for name := range f.Profiles {
+			profile = name
+		}
+	}
+
+	names := make([]string, 0, len(f.Profiles))
+
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	if profile == "" {
+		return settings{}, "", fmt.Errorf("no profile selected, set %s or default_profile to one of %s", EnvProfile, strings.Join(names, ", "))
+	}
+
+	s, ok := f.Profiles[profile]
+
+	if !ok {
+		return settings{}, "", fmt.Errorf("profile %q not found, expected one of %s", profile, strings.Join(names, ", "))
+	}
+
+	return s, profile, nil
+}
+
+// config parses the settings on top of DefaultConfig and validates the result. name returns how a field is
+// called in the errors
+func (s settings) config(name func(field string) string) (Config, error) {
+	config := DefaultConfig()
+	var problems []string
+
+	parse := func(field string, value string, parser func(string) error) {
+		if value == "" {
+			return
+		}
+
+		if err := parser(value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name(field), err))
+		}
+	}
+
+	parse("base_url", s.BaseURL, func(value string) error {
+		bu, err := url.Parse(value)
+		config.BaseURL = bu
+		return err
+	})
+	parse("timeout", s.Timeout, func(value string) (err error) {
+		config.Timeout, err = time.ParseDuration(value)
+		return err
+	})
+	parse("organisation_id", s.OrganisationID, func(value string) (err error) {
+		config.OrganisationID, err = uuid.Parse(value)
+		return err
+	})
+	parse("retry.max_attempts", s.Retry.MaxAttempts, func(value string) (err error) {
+		config.RetryPolicy.MaxAttempts, err = strconv.Atoi(value)
+		return err
+	})
+	parse("retry.base_delay", s.Retry.BaseDelay, func(value string) (err error) {
+		config.RetryPolicy.BaseDelay, err = time.ParseDuration(value)
+		return err
+	})
+	parse("retry.max_delay", s.Retry.MaxDelay, func(value string) (err error) {
+		config.RetryPolicy.MaxDelay, err = time.ParseDuration(value)
+		return err
+	})
+	parse("retry.jitter", s.Retry.Jitter, func(value string) (err error) {
+		config.RetryPolicy.Jitter, err = strconv.ParseFloat(value, 64)
+		return err
+	})
+	parse("retry.retry_post", s.Retry.RetryPost, func(value string) (err error) {
+		config.RetryPolicy.RetryPost, err = strconv.ParseBool(value)
+		return err
+	})
+
+	if s.UserAgent != "" {
+		config.UserAgent = s.UserAgent
+	}
+
+	if s.APIVersion != "" {
+		config.APIVersion = s.APIVersion
+	}
+
+	config.Credentials = Credentials{
+		ClientID:     s.Credentials.ClientID,
+		ClientSecret: s.Credentials.ClientSecret,
+	}
+
+	parse("credentials.token_url", s.Credentials.TokenURL, func(value string) (err error) {
+		config.Credentials.TokenURL, err = url.Parse(value)
+		return err
+	})
+
+	switch {
+	case s.Credentials.KeyID == "" && s.Credentials.PrivateKeyFile != "":
+		problems = append(problems, fmt.Sprintf("%s: required with %s", name("credentials.key_id"), name("credentials.private_key_file")))
+	case s.Credentials.KeyID != "" && s.Credentials.PrivateKeyFile == "":
+		problems = append(problems, fmt.Sprintf("%s: required with %s", name("credentials.private_key_file"), name("credentials.key_id")))
+	case s.Credentials.KeyID != "":
+		signer, err := client.NewHTTPSignerFromFile(s.Credentials.KeyID, s.Credentials.PrivateKeyFile)
+
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name("credentials.private_key_file"), err))
+		} else {
+			config.Signer = signer
+		}
+	}
+
+	if len(problems) > 0 {
+		return Config{}, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,218 @@
+package form3
+
+import (
+	"github.com/ioannisGiak89/accounts-api-client/pkg/fakeapi"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Sets environment variables for the duration of a test
+func setEnv(t *testing.T, env map[string]string) {
+	for key, value := range env {
+		previous, existed := os.LookupEnv(key)
+		require.NoError(t, os.Setenv(key, value))
+
+		key := key
+		t.Cleanup(func() {
+			if existed {
+				os.Setenv(key, previous)
+			} else {
+				os.Unsetenv(key)
+			}
+		})
+	}
+}
+
+// Writes a config file in a temporary folder
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func TestConfigFromEnv(t *testing.T) {
+
+	t.Run("should read every setting", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			EnvBaseURL:          "http://localhost:8080",
+			EnvTimeout:          "5s",
+			EnvUserAgent:        "reconciliation-job/1.0",
+			EnvAPIVersion:       "v2",
+			EnvOrganisationID:   "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+			EnvClientID:         "client",
+			EnvClientSecret:     "secret",
+			EnvTokenURL:         "https://auth.form3.tech/v1/oauth2/token",
+			EnvRetryMaxAttempts: "4",
+			EnvRetryBaseDelay:   "50ms",
+			EnvRetryMaxDelay:    "1s",
+			EnvRetryJitter:      "0.5",
+			EnvRetryPost:        "true",
+		})
+
+		config, err := ConfigFromEnv()
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://localhost:8080", config.BaseURL.String())
+		assert.Equal(t, 5*time.Second, config.Timeout)
+		assert.Equal(t, "reconciliation-job/1.0", config.UserAgent)
+		assert.Equal(t, "v2", config.APIVersion)
+		assert.Equal(t, testUtils.ParseUuid("eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"), config.OrganisationID)
+		assert.Equal(t, "client", config.Credentials.ClientID)
+		assert.Equal(t, "secret", config.Credentials.ClientSecret)
+		assert.Equal(t, "https://auth.form3.tech/v1/oauth2/token", config.Credentials.TokenURL.String())
+		assert.Equal(t, 4, config.RetryPolicy.MaxAttempts)
+		assert.Equal(t, 50*time.Millisecond, config.RetryPolicy.BaseDelay)
+		assert.Equal(t, time.Second, config.RetryPolicy.MaxDelay)
+		assert.Equal(t, 0.5, config.RetryPolicy.Jitter)
+		assert.True(t, config.RetryPolicy.RetryPost)
+	})
+
+	t.Run("should keep the defaults of unset variables", func(t *testing.T) {
+		setEnv(t, map[string]string{EnvBaseURL: "http://localhost:8080/"})
+
+		config, err := ConfigFromEnv()
+		require.NoError(t, err)
+
+		assert.Equal(t, DefaultConfig().Timeout, config.Timeout)
+		assert.Equal(t, DefaultConfig().RetryPolicy, config.RetryPolicy)
+	})
+
+	t.Run("should name the invalid variables", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			EnvBaseURL:          "http://localhost:8080/",
+			EnvTimeout:          "soon",
+			EnvRetryMaxAttempts: "many",
+		})
+
+		_, err := ConfigFromEnv()
+
+		require.NotNil(t, err)
+		assert.Contains(t, err.Error(), "FORM3_TIMEOUT: time: invalid duration")
+		assert.Contains(t, err.Error(), "FORM3_RETRY_MAX_ATTEMPTS: strconv.Atoi")
+	})
+
+	t.Run("should create a signer from the key file", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			EnvBaseURL:        "http://localhost:8080/",
+			EnvKeyID:          "my-key",
+			EnvPrivateKeyFile: "../lib/client/testdata/rsa_private_key.pem",
+		})
+
+		config, err := ConfigFromEnv()
+		require.NoError(t, err)
+
+		assert.NotNil(t, config.Signer)
+	})
+
+	t.Run("should require the key ID and the key file together", func(t *testing.T) {
+		setEnv(t, map[string]string{
+			EnvBaseURL:        "http://localhost:8080/",
+			EnvKeyID:          "",
+			EnvPrivateKeyFile: "key.pem",
+		})
+
+		_, err := ConfigFromEnv()
+
+		assert.EqualError(t, err, "form3: invalid environment: FORM3_KEY_ID: required with FORM3_PRIVATE_KEY_FILE")
+	})
+
+	t.Run("should require a base URL", func(t *testing.T) {
+		setEnv(t, map[string]string{EnvBaseURL: ""})
+
+		_, err := NewFromEnv()
+
+		assert.EqualError(t, err, "form3: invalid environment: form3: invalid config: base URL is required")
+	})
+
+	t.Run("should create a working lib", func(t *testing.T) {
+		server := fakeapi.NewServer()
+		defer server.Close()
+		setEnv(t, map[string]string{EnvBaseURL: server.URL})
+
+		f3, err := NewFromEnv()
+		require.NoError(t, err)
+
+		_, err = f3.Accounts.List(nil)
+		assert.Nil(t, err)
+	})
+}
+
+func TestLoadConfigFile(t *testing.T) {
+
+	profiles := `
+default_profile: sandbox
+profiles:
+  sandbox:
+    base_url: http://localhost:8080/
+    timeout: 10s
+    retry:
+      max_attempts: 3
+      base_delay: 100ms
+  prod:
+    base_url: https://api.form3.tech/
+    organisation_id: eb0bd6f5-c3f5-44b2-b677-acd23cdde73c
+    credentials:
+      client_id: client
+      client_secret: secret
+`
+
+	t.Run("should read the default profile", func(t *testing.T) {
+		config, err := LoadConfigFile(writeConfigFile(t, "form3.yaml", profiles), "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://localhost:8080/", config.BaseURL.String())
+		assert.Equal(t, 10*time.Second, config.Timeout)
+		assert.Equal(t, 3, config.RetryPolicy.MaxAttempts)
+		assert.Equal(t, 100*time.Millisecond, config.RetryPolicy.BaseDelay)
+	})
+
+	t.Run("should read the selected profile", func(t *testing.T) {
+		config, err := LoadConfigFile(writeConfigFile(t, "form3.yaml", profiles), "prod")
+		require.NoError(t, err)
+
+		assert.Equal(t, "https://api.form3.tech/", config.BaseURL.String())
+		assert.Equal(t, Credentials{ClientID: "client", ClientSecret: "secret"}, config.Credentials)
+		assert.Equal(t, DefaultConfig().Timeout, config.Timeout)
+	})
+
+	t.Run("should read a JSON file without profiles", func(t *testing.T) {
+		path := writeConfigFile(t, "form3.json", `{"base_url": "http://localhost:8080/", "retry": {"jitter": 0.1}}`)
+
+		config, err := LoadConfigFile(path, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://localhost:8080/", config.BaseURL.String())
+		assert.Equal(t, 0.1, config.RetryPolicy.Jitter)
+	})
+
+	t.Run("should return clear errors", func(t *testing.T) {
+		path := writeConfigFile(t, "form3.yaml", profiles)
+
+		_, err := LoadConfigFile(path, "staging")
+		assert.EqualError(t, err, "form3: config file "+path+`: profile "staging" not found, expected one of prod, sandbox`)
+
+		invalid := writeConfigFile(t, "invalid.yaml", "profiles:\n  sandbox:\n    base_url: http://localhost:8080/\n    timeout: soon\n")
+		_, err = LoadConfigFile(invalid, "")
+		assert.EqualError(t, err, "form3: config file "+invalid+`: profile sandbox: timeout: time: invalid duration "soon"`)
+
+		_, err = LoadConfigFile(filepath.Join(t.TempDir(), "missing.yaml"), "")
+		assert.Contains(t, err.Error(), "form3: reading config file")
+	})
+
+	t.Run("should select the profile with FORM3_PROFILE", func(t *testing.T) {
+		setEnv(t, map[string]string{EnvProfile: "prod"})
+		path := writeConfigFile(t, "form3.yaml", profiles)
+
+		f3, err := NewFromFile(path)
+
+		require.NoError(t, err)
+		assert.NotNil(t, f3.Accounts)
+	})
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors used to classify an APIError with errors.Is
+var (
+	ErrNotFound    = errors.New("form3: resource not found")
+	ErrConflict    = errors.New("form3: resource conflict")
+	ErrValidation  = errors.New("form3: validation failure")
+	ErrServerError = errors.New("form3: server error")
+)
+
+// APIError is returned when Form3 API responds with an unexpected status code
+type APIError struct {
+	StatusCode   int
+	ErrorCode    string
+	ErrorMessage string
+	Method       string
+	// URL is the URL of the request. The values of its filters are masked, as they can hold personal data
+	URL  string
+	Body []byte
+}
+
+// Form3 error response body
+type apiErrorBody struct {
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// newAPIError creates an APIError from a response and its already read body
+func newAPIError(res *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		Body:       body,
+	}
+
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		u := *res.Request.URL
+
+		if u.RawQuery != "" {
+			u.RawQuery = redactQuery(u.RawQuery)
+		}
+
+		apiErr.URL = u.String()
+	}
+
+	var errBody apiErrorBody
+
+	// Not every error response has a JSON body so a failed unmarshal is not an error here
+	if json.Unmarshal(body, &errBody) == nil {
+		apiErr.ErrorCode = errBody.ErrorCode
+		apiErr.ErrorMessage = errBody.ErrorMessage
+	}
+
+	return apiErr
+}
+
+// Error returns a description of the failed request
+func (e *APIError) Error() string {
+	message := e.ErrorMessage
+
+	if message == "" {
+		message = string(e.Body)
+	}
+
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+
+	if e.Method == "" {
+		return fmt.Sprintf("form3: status %d: %s", e.StatusCode, message)
+	}
+
+	return fmt.Sprintf("form3: %s %s: status %d: %s", e.Method, e.URL, e.StatusCode, message)
+}
+
+// Is reports whether the APIError matches one of the sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+
+	return false
+}
+
+// IsNotFound reports whether err is caused by a 404 response
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is caused by a 409 response, e.g. a version conflict or a duplicate account
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsValidation reports whether err is caused by a request that failed validation
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsServerError reports whether err is caused by a 5xx response
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServerError)
+}
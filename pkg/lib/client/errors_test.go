@@ -0,0 +1,122 @@
+package client_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+
+	t.Run("should parse the Form3 error body and the request details", func(t *testing.T) {
+		body := []byte(`{"error_code":"00000000-0000-0000-0000-000000000000","error_message":"invalid version"}`)
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						Body:       ioutil.NopCloser(bytes.NewReader(body)),
+						StatusCode: http.StatusConflict,
+						Request:    req,
+					}, nil
+				},
+			},
+		)
+
+		err := form3Client.Delete("v1/organisation/accounts/some-id?version=1")
+
+		var apiErr *client.APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000000", apiErr.ErrorCode)
+		assert.Equal(t, "invalid version", apiErr.ErrorMessage)
+		assert.Equal(t, http.MethodDelete, apiErr.Method)
+		assert.Equal(t, "http://localhost:8080/v1/organisation/accounts/some-id?version=1", apiErr.URL)
+		assert.Equal(t, body, apiErr.Body)
+		assert.Equal(
+			t,
+			"form3: DELETE http://localhost:8080/v1/organisation/accounts/some-id?version=1: status 409: invalid version",
+			apiErr.Error(),
+		)
+	})
+
+	t.Run("should mask the values of the filters in the URL", func(t *testing.T) {
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+						StatusCode: http.StatusBadRequest,
+						Request:    req,
+					}, nil
+				},
+			},
+		)
+
+		_, err := form3Client.Get("v1/organisation/accounts?filter%5Baccount_number%5D=41426819&page%5Bsize%5D=10&audit=a%26b%3Dc+d")
+
+		var apiErr *client.APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.NotContains(t, apiErr.Error(), "41426819")
+
+		u, err := url.Parse(apiErr.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "/v1/organisation/accounts", u.Path)
+		assert.Equal(t, url.Values{
+			"filter[account_number]": {client.Redacted},
+			"page[size]":             {"10"},
+			"audit":                  {"a&b=c d"},
+		}, u.Query())
+	})
+
+	t.Run("should classify errors by status code", func(t *testing.T) {
+		tests := []struct {
+			statusCode  int
+			notFound    bool
+			conflict    bool
+			validation  bool
+			serverError bool
+		}{
+			{statusCode: http.StatusBadRequest, validation: true},
+			{statusCode: http.StatusUnauthorized},
+			{statusCode: http.StatusNotFound, notFound: true},
+			{statusCode: http.StatusConflict, conflict: true},
+			{statusCode: http.StatusInternalServerError, serverError: true},
+			{statusCode: http.StatusServiceUnavailable, serverError: true},
+		}
+
+		for _, tt := range tests {
+			// Wrapped errors should be classified as well
+			err := fmt.Errorf("wrapped: %w", &client.APIError{StatusCode: tt.statusCode})
+
+			assert.Equal(t, tt.notFound, client.IsNotFound(err), tt.statusCode)
+			assert.Equal(t, tt.conflict, client.IsConflict(err), tt.statusCode)
+			assert.Equal(t, tt.validation, client.IsValidation(err), tt.statusCode)
+			assert.Equal(t, tt.serverError, client.IsServerError(err), tt.statusCode)
+		}
+	})
+
+	t.Run("should not classify other errors", func(t *testing.T) {
+		err := errors.New("network request failed")
+
+		assert.False(t, client.IsNotFound(err))
+		assert.False(t, client.IsServerError(err))
+		assert.False(t, client.IsNotFound(nil))
+	})
+
+	t.Run("should fall back to the raw body or the status text", func(t *testing.T) {
+		assert.Equal(t, "form3: status 404: not found", (&client.APIError{StatusCode: 404, Body: []byte("not found")}).Error())
+		assert.Equal(t, "form3: status 503: Service Unavailable", (&client.APIError{StatusCode: 503}).Error())
+	})
+}
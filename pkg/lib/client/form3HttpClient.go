@@ -2,18 +2,26 @@ package client
 
 import (
 	"bytes"
-	"errors"
+	"context"
 	"fmt"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 )
 
-// Form3ResourcesClient defines the Form3 Resources client
+// Form3ResourcesClient defines the Form3 Resources client. When the API responds with an unexpected
+// status code the returned error is an *APIError
 type Form3ResourcesClient interface {
 	Get(path string) ([]byte, error)
 	Delete(path string) error
 	Post(path string, body []byte) ([]byte, error)
+	GetWithContext(ctx context.Context, path string) ([]byte, error)
+	DeleteWithContext(ctx context.Context, path string) error
+	PostWithContext(ctx context.Context, path string, body []byte) ([]byte, error)
+	Patch(path string, body []byte) ([]byte, error)
+	PatchWithContext(ctx context.Context, path string, body []byte) ([]byte, error)
 }
 
 // HTTPClient interface. This interface is implemented by http.Client and is used for mocking
@@ -23,21 +31,87 @@ type HTTPClient interface {
 
 // Form3RestClient implements the Form3ResourcesClient interface
 type Form3RestClient struct {
-	baseUrl *url.URL
-	client  HTTPClient
+	baseUrl        *url.URL
+	client         HTTPClient
+	retryPolicy    RetryPolicy
+	userAgent      string
+	defaultHeaders http.Header
+	logger         Logger
+	signer         Signer
+	interceptors   []Interceptor
+	requestLogging bool
+	logBodies      bool
+	redactor       Redactor
+	metrics        MetricsRecorder
+	tracer         tracing.Tracer
 }
 
-// Creates a new Form3 rest client
-func NewForm3RestClient(baseUrl *url.URL, httpClient HTTPClient) *Form3RestClient {
-	return &Form3RestClient{
-		baseUrl: baseUrl,
-		client:  httpClient,
+// RestClientOption configures optional behaviour of a Form3RestClient
+type RestClientOption func(cl *Form3RestClient)
+
+// WithRetryPolicy sets the policy used to retry requests that failed with a transient error
+func WithRetryPolicy(policy RetryPolicy) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.retryPolicy = policy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request
+func WithUserAgent(userAgent string) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.userAgent = userAgent
+	}
+}
+
+// WithDefaultHeaders sets headers sent with every request
+func WithDefaultHeaders(headers http.Header) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.defaultHeaders = headers.Clone()
+	}
+}
+
+// WithTracer sets the tracer that records a span for every request, covering all its attempts. The span context
+// is propagated to the API with the traceparent header
+func WithTracer(tracer tracing.Tracer) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.tracer = tracer
+	}
+}
+
+// WithLogger sets the logger the client reports to
+func WithLogger(logger Logger) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.logger = logger
+	}
+}
+
+// Creates a new Form3 rest client. By default, requests are not retried and nothing is logged, measured or traced
+func NewForm3RestClient(baseUrl *url.URL, httpClient HTTPClient, opts ...RestClientOption) *Form3RestClient {
+	cl := &Form3RestClient{
+		baseUrl:     baseUrl,
+		client:      httpClient,
+		retryPolicy: NoRetryPolicy(),
+		logger:      noopLogger{},
+		redactor:    NewFieldRedactor(DefaultRedactedFields...),
+		metrics:     noopMetrics{},
+		tracer:      tracing.NoopTracer(),
+	}
+
+	for _, opt := range opts {
+		opt(cl)
 	}
+
+	return cl
 }
 
 // Get does a get request to an endpoint
 func (cl *Form3RestClient) Get(path string) ([]byte, error) {
-	res, err := cl.createAndDoRequest(http.MethodGet, path, nil)
+	return cl.GetWithContext(context.Background(), path)
+}
+
+// GetWithContext does a get request to an endpoint. The request is bound to ctx
+func (cl *Form3RestClient) GetWithContext(ctx context.Context, path string) ([]byte, error) {
+	res, err := cl.createAndDoRequest(ctx, http.MethodGet, path, nil)
 
 	if err != nil {
 		return nil, err
@@ -50,7 +124,7 @@ func (cl *Form3RestClient) Get(path string) ([]byte, error) {
 	}
 
 	if res.StatusCode != http.StatusOK {
-		return nil, errors.New(string(resBody))
+		return nil, newAPIError(res, resBody)
 	}
 
 	return resBody, nil
@@ -58,7 +132,12 @@ func (cl *Form3RestClient) Get(path string) ([]byte, error) {
 
 // Post does a post request to an endpoint
 func (cl *Form3RestClient) Post(path string, body []byte) ([]byte, error) {
-	res, err := cl.createAndDoRequest(http.MethodPost, path, body)
+	return cl.PostWithContext(context.Background(), path, body)
+}
+
+// PostWithContext does a post request to an endpoint. The request is bound to ctx
+func (cl *Form3RestClient) PostWithContext(ctx context.Context, path string, body []byte) ([]byte, error) {
+	res, err := cl.createAndDoRequest(ctx, http.MethodPost, path, body)
 
 	if err != nil {
 		return nil, err
@@ -71,7 +150,33 @@ func (cl *Form3RestClient) Post(path string, body []byte) ([]byte, error) {
 	}
 
 	if res.StatusCode != http.StatusCreated {
-		return nil, errors.New(string(resBody))
+		return nil, newAPIError(res, resBody)
+	}
+
+	return resBody, nil
+}
+
+// Patch does a patch request to an endpoint
+func (cl *Form3RestClient) Patch(path string, body []byte) ([]byte, error) {
+	return cl.PatchWithContext(context.Background(), path, body)
+}
+
+// PatchWithContext does a patch request to an endpoint. The request is bound to ctx
+func (cl *Form3RestClient) PatchWithContext(ctx context.Context, path string, body []byte) ([]byte, error) {
+	res, err := cl.createAndDoRequest(ctx, http.MethodPatch, path, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resBody, err := cl.readResponseBody(res)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, newAPIError(res, resBody)
 	}
 
 	return resBody, nil
@@ -79,7 +184,12 @@ func (cl *Form3RestClient) Post(path string, body []byte) ([]byte, error) {
 
 // Delete does a delete request to an endpoint
 func (cl *Form3RestClient) Delete(path string) error {
-	res, err := cl.createAndDoRequest(http.MethodDelete, path, nil)
+	return cl.DeleteWithContext(context.Background(), path)
+}
+
+// DeleteWithContext does a delete request to an endpoint. The request is bound to ctx
+func (cl *Form3RestClient) DeleteWithContext(ctx context.Context, path string) error {
+	res, err := cl.createAndDoRequest(ctx, http.MethodDelete, path, nil)
 
 	if err != nil {
 		return err
@@ -92,27 +202,153 @@ func (cl *Form3RestClient) Delete(path string) error {
 			return err
 		}
 
-		return errors.New(string(resBody))
+		return newAPIError(res, resBody)
 	}
 
 	return nil
 }
 
-// Private method that creates and does the request. Used to avoid code duplication
-func (cl *Form3RestClient) createAndDoRequest(httpMethod string, path string, body []byte) (*http.Response, error) {
-	req, err := http.NewRequest(httpMethod, fmt.Sprintf(
+// Private method that creates and does the request through the interceptors. Used to avoid code duplication
+func (cl *Form3RestClient) createAndDoRequest(ctx context.Context, httpMethod string, path string, body []byte) (*http.Response, error) {
+	req := &Request{
+		Method: httpMethod,
+		Path:   path,
+		Body:   body,
+		Header: http.Header{},
+	}
+
+	if key := IdempotencyKeyFromContext(ctx); key != "" && httpMethod == http.MethodPost {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	return chain(cl.interceptors, cl.send)(ctx, req)
+}
+
+// Private method that sends the request in a span of its own
+func (cl *Form3RestClient) send(ctx context.Context, req *Request) (*http.Response, error) {
+	ctx, span := cl.tracer.Start(ctx, "HTTP "+req.Method)
+	defer span.End()
+
+	span.SetAttribute(tracing.AttributeHTTPMethod, req.Method)
+	span.SetAttribute(tracing.AttributeHTTPPath, redactPath(req.Path))
+
+	res, retries, err := cl.sendWithRetries(ctx, req)
+	span.SetAttribute(tracing.AttributeRetries, retries)
+
+	if err != nil {
+		span.SetError(err)
+	} else {
+		span.SetAttribute(tracing.AttributeHTTPStatus, res.StatusCode)
+
+		if res.StatusCode >= http.StatusBadRequest {
+			span.SetError(fmt.Errorf("status %d", res.StatusCode))
+		}
+	}
+
+	return res, err
+}
+
+// Private method that sends the request, retrying it according to the retry policy. The number of retries
+// is returned with the response
+func (cl *Form3RestClient) sendWithRetries(ctx context.Context, req *Request) (*http.Response, int, error) {
+	retryAllowed := cl.retryPolicy.allowsRetry(req)
+	op := operationOf(req.Method, req.Path)
+
+	for attempt := 1; ; attempt++ {
+		// A request that can't be built or signed fails the same way on every attempt
+		httpReq, err := cl.newRequest(ctx, req)
+
+		if err != nil {
+			return nil, attempt - 1, err
+		}
+
+		res, err := cl.doRequest(ctx, req, httpReq, op, attempt)
+
+		if !retryAllowed || attempt >= cl.retryPolicy.MaxAttempts || !shouldRetry(ctx, res, err) {
+			return res, attempt - 1, err
+		}
+
+		delay := cl.retryPolicy.delay(attempt, res)
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"path":    redactPath(req.Path),
+			"attempt": attempt,
+			"delay":   delay,
+		}
+
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = res.StatusCode
+		}
+
+		cl.logger.Log(ctx, "form3: retrying request", fields)
+		cl.metrics.RequestRetried(op.resource, op.name)
+		discardResponse(res)
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
+
+// Private method that does a single attempt of a request, measuring it and logging it when request logging
+// is enabled
+func (cl *Form3RestClient) doRequest(ctx context.Context, r *Request, req *http.Request, op operation, attempt int) (*http.Response, error) {
+	cl.metrics.RequestStarted(op.resource, op.name)
+	start := time.Now()
+	res, err := cl.client.Do(req)
+	cl.metrics.RequestFinished(op.resource, op.name, statusClass(res, err), time.Since(start))
+
+	if cl.requestLogging {
+		cl.logAttempt(ctx, r, req, attempt, time.Since(start), res, err)
+	}
+
+	return res, err
+}
+
+// Private method that creates the request of an attempt. The body is wrapped in a new reader on every
+// attempt so it can be replayed
+func (cl *Form3RestClient) newRequest(ctx context.Context, r *Request) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, r.Method, fmt.Sprintf(
 		"%s%s",
 		cl.baseUrl.String(),
-		path,
-	), bytes.NewBuffer(body))
+		r.Path,
+	), bytes.NewReader(r.Body))
 
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Content-Type", "application/json")
+	for key, values := range cl.defaultHeaders {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	// Headers of the interceptors replace the default ones
+	for key, values := range r.Header {
+		req.Header.Del(key)
+
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if cl.userAgent != "" {
+		req.Header.Set("User-Agent", cl.userAgent)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	tracing.Inject(req.Header, tracing.SpanContextFromContext(ctx))
+
+	if cl.signer != nil {
+		if err := cl.signer.Sign(req, r.Body); err != nil {
+			return nil, err
+		}
+	}
 
-	return cl.client.Do(req)
+	return req, nil
 }
 
 // Private method that reads the response body. Used to avoid code duplication
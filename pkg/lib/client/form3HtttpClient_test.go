@@ -2,6 +2,7 @@ package client_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/google/uuid"
@@ -61,7 +62,8 @@ func TestHttpClient_Get(t *testing.T) {
 
 		responseBody, err := form3Client.Get("path/to/form3/resource/endpoint")
 
-		assert.Equal(t, errors.New("not found"), err)
+		assert.Equal(t, &client.APIError{StatusCode: http.StatusNotFound, Body: []byte("not found")}, err)
+		assert.True(t, client.IsNotFound(err))
 		assert.Nil(t, responseBody)
 	})
 
@@ -125,7 +127,8 @@ func TestHttpClient_Post(t *testing.T) {
 
 		responseBody, err := form3Client.Post("path/to/form3/resource/endpoint", bodyRequest)
 
-		assert.Equal(t, errors.New("conflict"), err)
+		assert.Equal(t, &client.APIError{StatusCode: http.StatusConflict, Body: []byte("conflict")}, err)
+		assert.True(t, client.IsConflict(err))
 		assert.Nil(t, responseBody)
 	})
 
@@ -149,6 +152,56 @@ func TestHttpClient_Post(t *testing.T) {
 	})
 }
 
+func TestHttpClient_Patch(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+	bodyRequest := []byte(`{"data":{"version":0}}`)
+
+	t.Run("should return an error if status code wasn't 200", func(t *testing.T) {
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte("invalid version"))),
+						StatusCode: http.StatusConflict,
+					}, nil
+				},
+			},
+		)
+
+		responseBody, err := form3Client.Patch("path/to/form3/resource/endpoint", bodyRequest)
+
+		assert.True(t, client.IsConflict(err))
+		assert.Nil(t, responseBody)
+	})
+
+	t.Run("should send the body and return the responseBody", func(t *testing.T) {
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, http.MethodPatch, req.Method)
+					sent, err := ioutil.ReadAll(req.Body)
+					require.NoError(t, err)
+					assert.Equal(t, bodyRequest, sent)
+
+					return &http.Response{
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte("Account Updated"))),
+						StatusCode: http.StatusOK,
+					}, nil
+				},
+			},
+		)
+
+		responseBody, err := form3Client.Patch("path/to/form3/resource/endpoint", bodyRequest)
+
+		assert.Equal(t, "Account Updated", string(responseBody))
+		assert.Nil(t, err)
+	})
+}
+
 func TestHttpClient_Delete(t *testing.T) {
 
 	baseURL, err := url.Parse("http://localhost:8080/")
@@ -184,7 +237,8 @@ func TestHttpClient_Delete(t *testing.T) {
 
 		err := form3Client.Delete("path/to/form3/resource/endpoint")
 
-		assert.Equal(t, errors.New("not found"), err)
+		assert.Equal(t, &client.APIError{StatusCode: http.StatusNotFound, Body: []byte("not found")}, err)
+		assert.True(t, client.IsNotFound(err))
 	})
 
 	t.Run("should nil if there is no error", func(t *testing.T) {
@@ -204,3 +258,45 @@ func TestHttpClient_Delete(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestHttpClient_WithContext(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+
+	t.Run("should bind the request to the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					assert.Equal(t, ctx, req.Context())
+					return &http.Response{
+						Body:       ioutil.NopCloser(bytes.NewReader([]byte("A valid account"))),
+						StatusCode: http.StatusOK,
+					}, nil
+				},
+			},
+		)
+
+		responseBody, err := form3Client.GetWithContext(ctx, "path/to/form3/resource/endpoint")
+
+		assert.Equal(t, "A valid account", string(responseBody))
+		assert.Nil(t, err)
+	})
+
+	t.Run("should return an error if the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		form3Client := client.NewForm3RestClient(baseURL, &http.Client{})
+
+		_, err := form3Client.PostWithContext(ctx, "path/to/form3/resource/endpoint", []byte("{}"))
+		assert.True(t, errors.Is(err, context.Canceled))
+
+		err = form3Client.DeleteWithContext(ctx, "path/to/form3/resource/endpoint")
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
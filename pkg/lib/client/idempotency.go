@@ -0,0 +1,20 @@
+package client
+
+import "context"
+
+// IdempotencyKeyHeader is the header that carries the idempotency key of a request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// ContextWithIdempotencyKey returns a context whose POST requests carry the idempotency key. POST requests are
+// still only retried when the retry policy has RetryPost set
+func ContextWithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key held by ctx, or an empty string
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Request is a request of the client as seen by the interceptors. Changes to it apply to every attempt
+type Request struct {
+	Method string
+	// Path is relative to the base URL of the client, including the query
+	Path string
+	Body []byte
+	// Header holds headers added to the request, on top of the default ones
+	Header http.Header
+}
+
+// Handler sends a request and returns the response
+type Handler func(ctx context.Context, req *Request) (*http.Response, error)
+
+// Interceptor wraps the sending of a request. It can change the request before calling next, inspect or replace
+// the response, or short-circuit by returning without calling next. A response returned without calling next must
+// have a Body
+type Interceptor func(ctx context.Context, req *Request, next Handler) (*http.Response, error)
+
+// WithInterceptors adds interceptors to the client. The first interceptor is the outermost one, it sees the
+// request first and the response last. Interceptors wrap all the attempts of a request
+func WithInterceptors(interceptors ...Interceptor) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.interceptors = append(cl.interceptors, interceptors...)
+	}
+}
+
+// chain wraps a handler with the interceptors
+func chain(interceptors []Interceptor, handler Handler) Handler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		next := handler
+
+		handler = func(ctx context.Context, req *Request) (*http.Response, error) {
+			return interceptor(ctx, req, next)
+		}
+	}
+
+	return handler
+}
@@ -0,0 +1,140 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestForm3RestClient_WithInterceptors(t *testing.T) {
+	bu, _ := url.Parse("https://api.form3.tech/")
+
+	t.Run("should run the interceptors in order around the request", func(t *testing.T) {
+		var calls []string
+		record := func(name string) client.Interceptor {
+			return func(ctx context.Context, req *client.Request, next client.Handler) (*http.Response, error) {
+				calls = append(calls, name+" "+req.Method+" "+req.Path)
+				res, err := next(ctx, req)
+				calls = append(calls, name+" "+res.Status)
+				return res, err
+			}
+		}
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, "do")
+				res := newResponse(http.StatusOK, "{}")
+				res.Status = "200 OK"
+				return res, nil
+			},
+		}
+
+		cl := client.NewForm3RestClient(
+			bu,
+			httpClient,
+			client.WithInterceptors(record("first")),
+			client.WithInterceptors(record("second")),
+		)
+
+		_, err := cl.Get("v1/organisation/accounts/")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"first GET v1/organisation/accounts/",
+			"second GET v1/organisation/accounts/",
+			"do",
+			"second 200 OK",
+			"first 200 OK",
+		}, calls)
+	})
+
+	t.Run("should send the changes of the interceptors", func(t *testing.T) {
+		var sent *http.Request
+		var sentBody []byte
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				sent = req
+				sentBody, _ = ioutil.ReadAll(req.Body)
+				return newResponse(http.StatusCreated, "{}"), nil
+			},
+		}
+
+		mutate := func(ctx context.Context, req *client.Request, next client.Handler) (*http.Response, error) {
+			req.Header.Set("X-Request-ID", "request-1")
+			req.Header.Set("X-Team", "payments")
+			req.Path += "?audit=true"
+			req.Body = bytes.ToUpper(req.Body)
+			return next(ctx, req)
+		}
+
+		cl := client.NewForm3RestClient(
+			bu,
+			httpClient,
+			client.WithDefaultHeaders(http.Header{"X-Team": {"core"}}),
+			client.WithInterceptors(mutate),
+		)
+
+		_, err := cl.Post("v1/organisation/accounts/", []byte(`{"data": {}}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, "https://api.form3.tech/v1/organisation/accounts/?audit=true", sent.URL.String())
+		assert.Equal(t, "request-1", sent.Header.Get("X-Request-ID"))
+		assert.Equal(t, []string{"payments"}, sent.Header.Values("X-Team"))
+		assert.Equal(t, `{"DATA": {}}`, string(sentBody))
+	})
+
+	t.Run("should short-circuit the request", func(t *testing.T) {
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				t.Fatal("the request was sent")
+				return nil, nil
+			},
+		}
+
+		cached := func(ctx context.Context, req *client.Request, next client.Handler) (*http.Response, error) {
+			return newResponse(http.StatusOK, `{"data": "cached"}`), nil
+		}
+
+		cl := client.NewForm3RestClient(bu, httpClient, client.WithInterceptors(cached))
+
+		body, err := cl.Get("v1/organisation/accounts/")
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"data": "cached"}`, string(body))
+	})
+
+	t.Run("should wrap all the attempts of a request", func(t *testing.T) {
+		calls := 0
+		attempts := 0
+		responses := []*http.Response{newResponse(http.StatusServiceUnavailable, ""), newResponse(http.StatusOK, "{}")}
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				attempts++
+				res := responses[0]
+				responses = responses[1:]
+				return res, nil
+			},
+		}
+
+		count := func(ctx context.Context, req *client.Request, next client.Handler) (*http.Response, error) {
+			calls++
+			return next(ctx, req)
+		}
+
+		cl := client.NewForm3RestClient(bu, httpClient, client.WithRetryPolicy(fastRetryPolicy()), client.WithInterceptors(count))
+
+		_, err := cl.Get("v1/organisation/accounts/")
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 2, attempts)
+	})
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader is the header that carries the ID of a request
+const RequestIDHeader = "X-Request-Id"
+
+// Logger receives the log entries of the client as a message with structured fields
+type Logger interface {
+	Log(ctx context.Context, msg string, fields map[string]interface{})
+}
+
+// LoggerFunc adapts a function to the Logger interface
+type LoggerFunc func(ctx context.Context, msg string, fields map[string]interface{})
+
+// Log calls f
+func (f LoggerFunc) Log(ctx context.Context, msg string, fields map[string]interface{}) {
+	f(ctx, msg, fields)
+}
+
+// stdLogger writes the entries to a standard library logger
+type stdLogger struct {
+	logger *log.Logger
+}
+
+// NewStdLogger creates a Logger that writes every entry as a line of sorted key=value pairs to l
+func NewStdLogger(l *log.Logger) Logger {
+	return &stdLogger{logger: l}
+}
+
+// Log writes the entry
+func (l *stdLogger) Log(ctx context.Context, msg string, fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var line strings.Builder
+	line.WriteString(msg)
+
+	for _, key := range keys {
+		fmt.Fprintf(&line, " %s=%v", key, fields[key])
+	}
+
+	l.logger.Print(line.String())
+}
+
+// noopLogger discards every entry. Used when no logger is configured
+type noopLogger struct{}
+
+func (noopLogger) Log(ctx context.Context, msg string, fields map[string]interface{}) {}
+
+// WithRequestLogging logs every attempt of a request with its method, path, status, latency, request ID and
+// attempt number. With logBodies, the request and response bodies are logged too, masked by the redactor
+func WithRequestLogging(logBodies bool) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.requestLogging = true
+		cl.logBodies = logBodies
+	}
+}
+
+// WithRedactor sets the redactor that masks the logged bodies. By default, the DefaultRedactedFields are masked
+func WithRedactor(redactor Redactor) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.redactor = redactor
+	}
+}
+
+// logAttempt logs an attempt of a request. The response body is read to be logged and replaced with a copy
+func (cl *Form3RestClient) logAttempt(
+	ctx context.Context,
+	r *Request,
+	req *http.Request,
+	attempt int,
+	latency time.Duration,
+	res *http.Response,
+	err error,
+) {
+	fields := map[string]interface{}{
+		"method":  r.Method,
+		"path":    redactPath(r.Path),
+		"attempt": attempt,
+		"latency": latency,
+	}
+
+	requestID := req.Header.Get(RequestIDHeader)
+
+	if err != nil {
+		fields["error"] = err.Error()
+	} else {
+		fields["status"] = res.StatusCode
+
+		if id := res.Header.Get(RequestIDHeader); id != "" {
+			requestID = id
+		}
+	}
+
+	if requestID != "" {
+		fields["request_id"] = requestID
+	}
+
+	if cl.logBodies {
+		if len(r.Body) > 0 {
+			fields["request_body"] = cl.redactor(r.Body)
+		}
+
+		if res != nil && res.Body != nil {
+			body, readErr := ioutil.ReadAll(res.Body)
+			res.Body.Close()
+			res.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{readErr}))
+
+			if len(body) > 0 {
+				fields["response_body"] = cl.redactor(body)
+			}
+		}
+	}
+
+	cl.logger.Log(ctx, "form3: request", fields)
+}
+
+// errReader returns the error of a body that failed to be read for logging, or io.EOF
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return 0, io.EOF
+}
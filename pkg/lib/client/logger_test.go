@@ -0,0 +1,160 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewStdLogger(t *testing.T) {
+
+	t.Run("should write the message and the sorted fields", func(t *testing.T) {
+		var out bytes.Buffer
+		logger := client.NewStdLogger(log.New(&out, "", 0))
+
+		logger.Log(context.Background(), "form3: retrying request", map[string]interface{}{
+			"path":    "v1/organisation/accounts/",
+			"attempt": 1,
+			"method":  "GET",
+		})
+
+		assert.Equal(t, "form3: retrying request attempt=1 method=GET path=v1/organisation/accounts/\n", out.String())
+	})
+}
+
+// Records the entries of a logger
+type recordingLogger struct {
+	messages []string
+	fields   []map[string]interface{}
+}
+
+func (l *recordingLogger) Log(ctx context.Context, msg string, fields map[string]interface{}) {
+	l.messages = append(l.messages, msg)
+	l.fields = append(l.fields, fields)
+}
+
+func TestForm3RestClient_WithRequestLogging(t *testing.T) {
+	bu, _ := url.Parse("https://api.form3.tech/")
+
+	t.Run("should log every attempt", func(t *testing.T) {
+		logger := &recordingLogger{}
+		responses := []*http.Response{newResponse(http.StatusServiceUnavailable, ""), newResponse(http.StatusOK, "{}")}
+		responses[1].Header.Set("X-Request-Id", "request-1")
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				res := responses[0]
+				responses = responses[1:]
+				return res, nil
+			},
+		}
+
+		cl := client.NewForm3RestClient(
+			bu,
+			httpClient,
+			client.WithRetryPolicy(fastRetryPolicy()),
+			client.WithLogger(logger),
+			client.WithRequestLogging(false),
+		)
+
+		_, err := cl.Get("v1/organisation/accounts/")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"form3: request", "form3: retrying request", "form3: request"}, logger.messages)
+
+		first, last := logger.fields[0], logger.fields[2]
+		assert.Equal(t, "GET", first["method"])
+		assert.Equal(t, "v1/organisation/accounts/", first["path"])
+		assert.Equal(t, 1, first["attempt"])
+		assert.Equal(t, http.StatusServiceUnavailable, first["status"])
+		assert.IsType(t, time.Duration(0), first["latency"])
+		assert.NotContains(t, first, "request_id")
+		assert.Equal(t, 2, last["attempt"])
+		assert.Equal(t, http.StatusOK, last["status"])
+		assert.Equal(t, "request-1", last["request_id"])
+		assert.NotContains(t, last, "response_body")
+	})
+
+	t.Run("should log the redacted bodies and keep the response body", func(t *testing.T) {
+		logger := &recordingLogger{}
+		response := `{"data":{"attributes":{"country":"GB","iban":"GB33BUKB20201555555555","name":["Jane Doe"]}}}`
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				return newResponse(http.StatusCreated, response), nil
+			},
+		}
+
+		cl := client.NewForm3RestClient(bu, httpClient, client.WithLogger(logger), client.WithRequestLogging(true))
+
+		body, err := cl.Post("v1/organisation/accounts/", []byte(`{"data":{"attributes":{"account_number":"41426819"}}}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, response, string(body))
+		require.Len(t, logger.fields, 1)
+		assert.Equal(t, `{"data":{"attributes":{"account_number":"[REDACTED]"}}}`, logger.fields[0]["request_body"])
+		assert.Equal(
+			t,
+			`{"data":{"attributes":{"country":"GB","iban":"[REDACTED]","name":"[REDACTED]"}}}`,
+			logger.fields[0]["response_body"],
+		)
+	})
+
+	t.Run("should not log the values of the filters", func(t *testing.T) {
+		logger := &recordingLogger{}
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				res := newResponse(http.StatusServiceUnavailable, "")
+				res.Request = req
+				return res, nil
+			},
+		}
+
+		cl := client.NewForm3RestClient(
+			bu,
+			httpClient,
+			client.WithRetryPolicy(fastRetryPolicy()),
+			client.WithLogger(logger),
+			client.WithRequestLogging(false),
+		)
+
+		_, err := cl.Get("v1/organisation/accounts?filter%5Biban%5D=GB16NWBK40030041426819&page%5Bnumber%5D=1")
+		require.Error(t, err)
+
+		require.NotEmpty(t, logger.fields)
+
+		for _, fields := range logger.fields {
+			assert.Equal(t, "v1/organisation/accounts?filter%5Biban%5D=%5BREDACTED%5D&page%5Bnumber%5D=1", fields["path"])
+		}
+
+		assert.Equal(t, 5, len(logger.fields))
+		assert.NotContains(t, err.Error(), "GB16NWBK40030041426819")
+	})
+
+	t.Run("should log the errors", func(t *testing.T) {
+		logger := &recordingLogger{}
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			},
+		}
+
+		cl := client.NewForm3RestClient(bu, httpClient, client.WithLogger(logger), client.WithRequestLogging(true))
+
+		_, err := cl.Get("v1/organisation/accounts/")
+		require.Error(t, err)
+
+		require.Len(t, logger.fields, 1)
+		assert.Equal(t, "connection refused", logger.fields[0]["error"])
+	})
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetricsRecorder receives the measurements of every attempt of a request. resource is the kind of resource
+// requested, e.g. accounts, and operation is what was done with it: fetch, list, create, update or delete
+type MetricsRecorder interface {
+	// RequestStarted is called before an attempt is sent
+	RequestStarted(resource string, operation string)
+	// RequestFinished is called when an attempt got a response or failed. statusClass is 2xx, 3xx, 4xx, 5xx,
+	// or error when there is no response
+	RequestFinished(resource string, operation string, statusClass string, latency time.Duration)
+	// RequestRetried is called when a request is retried
+	RequestRetried(resource string, operation string)
+}
+
+// WithMetrics sets the recorder that receives the measurements of the client
+func WithMetrics(recorder MetricsRecorder) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.metrics = recorder
+	}
+}
+
+// operation describes what a request does, for the metrics
+type operation struct {
+	resource string
+	name     string
+}
+
+// operationOf derives the resource and the operation of a request from its method and path. The resource is
+// the last segment of the path that isn't an ID
+func operationOf(method string, path string) operation {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	resource := segments[len(segments)-1]
+	withID := false
+
+	if _, err := uuid.Parse(resource); err == nil && len(segments) > 1 {
+		resource = segments[len(segments)-2]
+		withID = true
+	}
+
+	var name string
+
+	switch method {
+	case http.MethodGet:
+		name = "list"
+
+		if withID {
+			name = "fetch"
+		}
+	case http.MethodPost:
+		name = "create"
+	case http.MethodPatch:
+		name = "update"
+	case http.MethodDelete:
+		name = "delete"
+	default:
+		name = strings.ToLower(method)
+	}
+
+	return operation{resource: resource, name: name}
+}
+
+// statusClass returns the class of the status of a response, or error when there is none
+func statusClass(res *http.Response, err error) string {
+	if err != nil || res == nil {
+		return "error"
+	}
+
+	switch {
+	case res.StatusCode >= 500:
+		return "5xx"
+	case res.StatusCode >= 400:
+		return "4xx"
+	case res.StatusCode >= 300:
+		return "3xx"
+	case res.StatusCode >= 200:
+		return "2xx"
+	}
+
+	return "1xx"
+}
+
+// noopMetrics discards every measurement. Used when no recorder is configured
+type noopMetrics struct{}
+
+func (noopMetrics) RequestStarted(resource string, operation string) {}
+
+func (noopMetrics) RequestFinished(resource string, operation string, statusClass string, latency time.Duration) {
+}
+
+func (noopMetrics) RequestRetried(resource string, operation string) {}
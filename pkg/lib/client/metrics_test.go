@@ -0,0 +1,129 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Records the measurements of a client
+type recordingMetrics struct {
+	calls []string
+}
+
+func (m *recordingMetrics) RequestStarted(resource string, operation string) {
+	m.calls = append(m.calls, fmt.Sprintf("started %s %s", resource, operation))
+}
+
+func (m *recordingMetrics) RequestFinished(resource string, operation string, statusClass string, latency time.Duration) {
+	m.calls = append(m.calls, fmt.Sprintf("finished %s %s %s", resource, operation, statusClass))
+}
+
+func (m *recordingMetrics) RequestRetried(resource string, operation string) {
+	m.calls = append(m.calls, fmt.Sprintf("retried %s %s", resource, operation))
+}
+
+func TestForm3RestClient_WithMetrics(t *testing.T) {
+	bu, _ := url.Parse("https://api.form3.tech/")
+	accountPath := "v1/organisation/accounts/ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"
+
+	t.Run("should measure every attempt by resource and operation", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+		statuses := []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK, http.StatusNotFound}
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				status := statuses[0]
+				statuses = statuses[1:]
+				return newResponse(status, "{}"), nil
+			},
+		}
+
+		cl := client.NewForm3RestClient(bu, httpClient, client.WithRetryPolicy(fastRetryPolicy()), client.WithMetrics(metrics))
+
+		_, err := cl.Get(accountPath)
+		require.NoError(t, err)
+		_, err = cl.Get("v1/organisation/accounts?page[size]=10")
+		require.NoError(t, err)
+		err = cl.Delete(accountPath + "?version=0")
+		require.Error(t, err)
+
+		assert.Equal(t, []string{
+			"started accounts fetch",
+			"finished accounts fetch 5xx",
+			"retried accounts fetch",
+			"started accounts fetch",
+			"finished accounts fetch 2xx",
+			"started accounts list",
+			"finished accounts list 2xx",
+			"started accounts delete",
+			"finished accounts delete 4xx",
+		}, metrics.calls)
+	})
+
+	t.Run("should measure the transport errors", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("connection refused")
+			},
+		}
+
+		cl := client.NewForm3RestClient(bu, httpClient, client.WithMetrics(metrics))
+
+		_, err := cl.Post("v1/organisation/accounts/", []byte("{}"))
+		require.Error(t, err)
+
+		assert.Equal(t, []string{"started accounts create", "finished accounts create error"}, metrics.calls)
+	})
+}
+
+func TestPrometheusRecorder(t *testing.T) {
+
+	t.Run("should serve the metrics in the text exposition format", func(t *testing.T) {
+		recorder := client.NewPrometheusRecorder(0.1, 1)
+		recorder.RequestStarted("accounts", "fetch")
+		recorder.RequestFinished("accounts", "fetch", "5xx", 50*time.Millisecond)
+		recorder.RequestRetried("accounts", "fetch")
+		recorder.RequestStarted("accounts", "fetch")
+		recorder.RequestFinished("accounts", "fetch", "2xx", 500*time.Millisecond)
+		recorder.RequestStarted("accounts", "create")
+
+		res := httptest.NewRecorder()
+		recorder.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		body, _ := ioutil.ReadAll(res.Body)
+
+		assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", res.Header().Get("Content-Type"))
+		assert.Equal(t, strings.Join([]string{
+			"# HELP form3_client_requests_total Requests sent to the Form3 API, including retries.",
+			"# TYPE form3_client_requests_total counter",
+			`form3_client_requests_total{operation="fetch",resource="accounts",status_class="2xx"} 1`,
+			`form3_client_requests_total{operation="fetch",resource="accounts",status_class="5xx"} 1`,
+			"# HELP form3_client_request_duration_seconds Latency of the requests sent to the Form3 API.",
+			"# TYPE form3_client_request_duration_seconds histogram",
+			`form3_client_request_duration_seconds_bucket{le="0.1",operation="fetch",resource="accounts"} 1`,
+			`form3_client_request_duration_seconds_bucket{le="1",operation="fetch",resource="accounts"} 2`,
+			`form3_client_request_duration_seconds_bucket{le="+Inf",operation="fetch",resource="accounts"} 2`,
+			`form3_client_request_duration_seconds_sum{operation="fetch",resource="accounts"} 0.55`,
+			`form3_client_request_duration_seconds_count{operation="fetch",resource="accounts"} 2`,
+			"# HELP form3_client_retries_total Requests to the Form3 API that were retried.",
+			"# TYPE form3_client_retries_total counter",
+			`form3_client_retries_total{operation="fetch",resource="accounts"} 1`,
+			"# HELP form3_client_requests_in_flight Requests to the Form3 API waiting for a response.",
+			"# TYPE form3_client_requests_in_flight gauge",
+			`form3_client_requests_in_flight{operation="create",resource="accounts"} 1`,
+			`form3_client_requests_in_flight{operation="fetch",resource="accounts"} 0`,
+			"",
+		}, "\n"), string(body))
+	})
+}
@@ -0,0 +1,283 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTokenExpiryDelta is how long before its expiry a token is refreshed
+	DefaultTokenExpiryDelta = 30 * time.Second
+	// DefaultTokenTimeout is how long a token request can take before it fails
+	DefaultTokenTimeout = 30 * time.Second
+)
+
+// Token is an OAuth2 access token
+type Token struct {
+	AccessToken string
+	TokenType   string
+	// Expiry is when the token expires. The zero value means it doesn't expire
+	Expiry time.Time
+}
+
+// tokenResponse is the body of a successful token response
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// tokenCall is a token request shared by every goroutine that needs a new token at the same time
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+// ClientCredentialsTokenSource obtains tokens from a token endpoint with the OAuth2 client credentials grant.
+// Tokens are cached until shortly before they expire and concurrent callers share a single refresh
+type ClientCredentialsTokenSource struct {
+	tokenURL     *url.URL
+	clientID     string
+	clientSecret string
+	client       HTTPClient
+	expiryDelta  time.Duration
+	timeout      time.Duration
+
+	mu       sync.Mutex
+	token    *Token
+	inflight *tokenCall
+}
+
+// TokenSourceOption configures a ClientCredentialsTokenSource
+type TokenSourceOption func(s *ClientCredentialsTokenSource)
+
+// WithTokenHTTPClient sets the HTTP client that requests the tokens
+func WithTokenHTTPClient(httpClient HTTPClient) TokenSourceOption {
+	return func(s *ClientCredentialsTokenSource) {
+		s.client = httpClient
+	}
+}
+
+// WithTokenExpiryDelta sets how long before its expiry a token is refreshed
+func WithTokenExpiryDelta(delta time.Duration) TokenSourceOption {
+	return func(s *ClientCredentialsTokenSource) {
+		s.expiryDelta = delta
+	}
+}
+
+// WithTokenTimeout sets how long a token request can take before it fails. The callers waiting for a token get
+// the error and the next call requests a new one
+func WithTokenTimeout(timeout time.Duration) TokenSourceOption {
+	return func(s *ClientCredentialsTokenSource) {
+		s.timeout = timeout
+	}
+}
+
+// NewClientCredentialsTokenSource creates a ClientCredentialsTokenSource. By default, tokens are requested with a
+// new http.Client, fail after DefaultTokenTimeout and are refreshed DefaultTokenExpiryDelta before they expire
+func NewClientCredentialsTokenSource(
+	tokenURL *url.URL,
+	clientID string,
+	clientSecret string,
+	opts ...TokenSourceOption,
+) *ClientCredentialsTokenSource {
+	s := &ClientCredentialsTokenSource{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       &http.Client{Timeout: DefaultTokenTimeout},
+		expiryDelta:  DefaultTokenExpiryDelta,
+		timeout:      DefaultTokenTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Token returns the cached token, or requests a new one when there is none or it is about to expire
+func (s *ClientCredentialsTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+
+	if s.token != nil && s.valid(s.token) {
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+
+	call := s.inflight
+
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		s.inflight = call
+
+		// The request outlives the caller that started it, as other callers wait for it too
+		go s.refresh(call)
+	}
+
+	s.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Invalidate drops the cached token if it is still the given one, e.g. after the API rejected it
+func (s *ClientCredentialsTokenSource) Invalidate(token *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = nil
+	}
+}
+
+// valid reports whether a token can still be used
+func (s *ClientCredentialsTokenSource) valid(token *Token) bool {
+	return token.Expiry.IsZero() || time.Now().Add(s.expiryDelta).Before(token.Expiry)
+}
+
+// refresh requests a new token and hands it to every caller waiting for the call
+func (s *ClientCredentialsTokenSource) refresh(call *tokenCall) {
+	call.token, call.err = s.requestToken()
+
+	s.mu.Lock()
+
+	if call.err == nil {
+		s.token = call.token
+	}
+
+	s.inflight = nil
+	s.mu.Unlock()
+
+	close(call.done)
+}
+
+// requestToken does the token request. It is bound by the timeout of the source rather than by a caller, so a
+// token endpoint that doesn't respond can't block the callers waiting for the refresh forever
+func (s *ClientCredentialsTokenSource) requestToken() (*Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL.String(), strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	issuedAt := time.Now()
+	res, err := s.client.Do(req)
+
+	if err != nil {
+		return nil, fmt.Errorf("form3: requesting token: %w", err)
+	}
+
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+
+	if err != nil {
+		return nil, fmt.Errorf("form3: reading token response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("form3: requesting token: status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var tr tokenResponse
+
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("form3: decoding token response: %w", err)
+	}
+
+	if tr.AccessToken == "" {
+		return nil, fmt.Errorf("form3: token response has no access token")
+	}
+
+	token := &Token{AccessToken: tr.AccessToken, TokenType: tr.TokenType}
+
+	if tr.ExpiresIn > 0 {
+		token.Expiry = issuedAt.Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+// OAuth2Client is an HTTPClient that authenticates every request with a bearer token. A request rejected with
+// a 401 is sent once more with a new token
+type OAuth2Client struct {
+	client HTTPClient
+	tokens *ClientCredentialsTokenSource
+}
+
+// NewOAuth2Client creates an OAuth2Client that sends the requests with httpClient
+func NewOAuth2Client(httpClient HTTPClient, tokens *ClientCredentialsTokenSource) *OAuth2Client {
+	return &OAuth2Client{
+		client: httpClient,
+		tokens: tokens,
+	}
+}
+
+// Do sends the request with a bearer token
+func (c *OAuth2Client) Do(req *http.Request) (*http.Response, error) {
+	token, err := c.tokens.Token(req.Context())
+
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.client.Do(authorize(req, token))
+
+	// The body of the request can't be sent twice without GetBody
+	if err != nil || res.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return res, err
+	}
+
+	discardResponse(res)
+	c.tokens.Invalidate(token)
+
+	if token, err = c.tokens.Token(req.Context()); err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+
+	if req.Body != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.client.Do(authorize(retry, token))
+}
+
+// authorize returns a copy of the request with the Authorization header of the token
+func authorize(req *http.Request, token *Token) *http.Request {
+	authorized := req.Clone(req.Context())
+	tokenType := token.TokenType
+
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+
+	authorized.Header.Set("Authorization", tokenType+" "+token.AccessToken)
+
+	return authorized
+}
@@ -0,0 +1,231 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tokenServer is a local token endpoint that issues numbered tokens
+type tokenServer struct {
+	*httptest.Server
+	issued    int32
+	expiresIn int
+	delay     time.Duration
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	ts := &tokenServer{expiresIn: expiresIn}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+
+		if !ok || clientID != "client" || clientSecret != "secret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": "invalid_client"}`)
+			return
+		}
+
+		time.Sleep(ts.delay)
+		issued := atomic.AddInt32(&ts.issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "bearer", "expires_in": %d}`, issued, ts.expiresIn)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func (ts *tokenServer) tokenSource(clientSecret string, opts ...client.TokenSourceOption) *client.ClientCredentialsTokenSource {
+	tokenURL, _ := url.Parse(ts.URL + "/v1/oauth2/token")
+
+	return client.NewClientCredentialsTokenSource(tokenURL, "client", clientSecret, opts...)
+}
+
+func TestClientCredentialsTokenSource_Token(t *testing.T) {
+	t.Run("should cache the token until shortly before it expires", func(t *testing.T) {
+		ts := newTokenServer(t, 3600)
+		tokens := ts.tokenSource("secret")
+
+		first, err := tokens.Token(context.Background())
+		require.NoError(t, err)
+		second, err := tokens.Token(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "token-1", first.AccessToken)
+		assert.Same(t, first, second)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), first.Expiry, time.Minute)
+
+		expiring := newTokenServer(t, 10).tokenSource("secret", client.WithTokenExpiryDelta(time.Minute))
+
+		first, err = expiring.Token(context.Background())
+		require.NoError(t, err)
+		second, err = expiring.Token(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "token-1", first.AccessToken)
+		assert.Equal(t, "token-2", second.AccessToken)
+	})
+
+	t.Run("should refresh once for concurrent callers", func(t *testing.T) {
+		ts := newTokenServer(t, 3600)
+		ts.delay = 50 * time.Millisecond
+		tokens := ts.tokenSource("secret")
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				token, err := tokens.Token(context.Background())
+				assert.NoError(t, err)
+				assert.Equal(t, "token-1", token.AccessToken)
+			}()
+		}
+
+		wg.Wait()
+		assert.EqualValues(t, 1, atomic.LoadInt32(&ts.issued))
+	})
+
+	t.Run("should return the error of the token endpoint", func(t *testing.T) {
+		tokens := newTokenServer(t, 3600).tokenSource("wrong")
+
+		_, err := tokens.Token(context.Background())
+
+		assert.EqualError(t, err, `form3: requesting token: status 401: {"error": "invalid_client"}`)
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		ts := newTokenServer(t, 3600)
+		ts.delay = time.Second
+		tokens := ts.tokenSource("secret")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := tokens.Token(ctx)
+
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+
+	t.Run("should fail the refresh when the token endpoint never responds", func(t *testing.T) {
+		hang := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-hang
+		}))
+		defer server.Close()
+		defer close(hang)
+
+		tokenURL, _ := url.Parse(server.URL + "/v1/oauth2/token")
+		tokens := client.NewClientCredentialsTokenSource(
+			tokenURL,
+			"client",
+			"secret",
+			client.WithTokenHTTPClient(&http.Client{}),
+			client.WithTokenTimeout(20*time.Millisecond),
+		)
+
+		_, err := tokens.Token(context.Background())
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+
+		_, err = tokens.Token(context.Background())
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "%v", err)
+	})
+}
+
+func TestOAuth2Client_Do(t *testing.T) {
+	t.Run("should send the bearer token", func(t *testing.T) {
+		var authorizations []string
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+		}))
+		defer api.Close()
+
+		ts := newTokenServer(t, 3600)
+		httpClient := client.NewOAuth2Client(&http.Client{}, ts.tokenSource("secret"))
+
+		for i := 0; i < 2; i++ {
+			req, _ := http.NewRequest(http.MethodGet, api.URL, nil)
+			res, err := httpClient.Do(req)
+			require.NoError(t, err)
+			res.Body.Close()
+		}
+
+		assert.Equal(t, []string{"Bearer token-1", "Bearer token-1"}, authorizations)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&ts.issued))
+	})
+
+	t.Run("should retry once with a new token on a 401", func(t *testing.T) {
+		var authorizations []string
+		var bodies []string
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+
+			if r.Header.Get("Authorization") == "Bearer token-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer api.Close()
+
+		bu, _ := url.Parse(api.URL + "/")
+		httpClient := client.NewOAuth2Client(&http.Client{}, newTokenServer(t, 3600).tokenSource("secret"))
+		cl := client.NewForm3RestClient(bu, httpClient)
+
+		_, err := cl.Post("v1/organisation/accounts/", []byte(`{"data": {}}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, authorizations)
+		assert.Equal(t, []string{`{"data": {}}`, `{"data": {}}`}, bodies)
+	})
+
+	t.Run("should return the second 401", func(t *testing.T) {
+		calls := 0
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer api.Close()
+
+		bu, _ := url.Parse(api.URL + "/")
+		httpClient := client.NewOAuth2Client(&http.Client{}, newTokenServer(t, 3600).tokenSource("secret"))
+		cl := client.NewForm3RestClient(bu, httpClient)
+
+		_, err := cl.Get("v1/organisation/accounts/")
+
+		var apiErr *client.APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should not send the request without a token", func(t *testing.T) {
+		httpClient := client.NewOAuth2Client(&mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				t.Fatal("an unauthenticated request was sent")
+				return nil, nil
+			},
+		}, newTokenServer(t, 3600).tokenSource("wrong"))
+
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost/", bytes.NewReader([]byte("{}")))
+		_, err := httpClient.Do(req)
+
+		assert.Error(t, err)
+	})
+}
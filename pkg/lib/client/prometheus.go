@@ -0,0 +1,238 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histogram buckets
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Names of the metrics exposed by the PrometheusRecorder
+const (
+	MetricRequestsTotal   = "form3_client_requests_total"
+	MetricRequestDuration = "form3_client_request_duration_seconds"
+	MetricRetriesTotal    = "form3_client_retries_total"
+	MetricInFlight        = "form3_client_requests_in_flight"
+)
+
+// seriesKey identifies the series of a metric by its label values
+type seriesKey struct {
+	resource    string
+	operation   string
+	statusClass string
+}
+
+// histogram counts the observations of every bucket, cumulatively when exposed
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// PrometheusRecorder is a MetricsRecorder that keeps the metrics in memory and serves them in the Prometheus
+// text exposition format. It is safe for concurrent use
+type PrometheusRecorder struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[seriesKey]uint64
+	durations map[seriesKey]*histogram
+	retries   map[seriesKey]uint64
+	inFlight  map[seriesKey]int64
+}
+
+// NewPrometheusRecorder creates a PrometheusRecorder with the given latency buckets, in seconds. When none are
+// given, the DefaultLatencyBuckets are used
+func NewPrometheusRecorder(buckets ...float64) *PrometheusRecorder {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &PrometheusRecorder{
+		buckets:   sorted,
+		requests:  map[seriesKey]uint64{},
+		durations: map[seriesKey]*histogram{},
+		retries:   map[seriesKey]uint64{},
+		inFlight:  map[seriesKey]int64{},
+	}
+}
+
+// RequestStarted increases the in-flight gauge
+func (r *PrometheusRecorder) RequestStarted(resource string, operation string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inFlight[seriesKey{resource: resource, operation: operation}]++
+}
+
+// RequestFinished decreases the in-flight gauge, counts the request and observes its latency
+func (r *PrometheusRecorder) RequestFinished(resource string, operation string, statusClass string, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := seriesKey{resource: resource, operation: operation}
+	r.inFlight[key]--
+	r.requests[seriesKey{resource: resource, operation: operation, statusClass: statusClass}]++
+
+	h, ok := r.durations[key]
+
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(r.buckets))}
+		r.durations[key] = h
+	}
+
+	seconds := latency.Seconds()
+
+	for i, bound := range r.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+
+	h.count++
+	h.sum += seconds
+}
+
+// RequestRetried counts the retry
+func (r *PrometheusRecorder) RequestRetried(resource string, operation string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.retries[seriesKey{resource: resource, operation: operation}]++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (r *PrometheusRecorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	r.WriteMetrics(w)
+}
+
+// WriteMetrics writes the metrics in the Prometheus text exposition format, with the series sorted by their labels
+func (r *PrometheusRecorder) WriteMetrics(out io.Writer) error {
+	w := bufio.NewWriter(out)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	writeHeader(w, MetricRequestsTotal, "counter", "Requests sent to the Form3 API, including retries.")
+
+	for _, key := range sortedKeys(r.requests) {
+		fmt.Fprintf(w, "%s%s %d\n", MetricRequestsTotal, labels(key, ""), r.requests[key])
+	}
+
+	writeHeader(w, MetricRequestDuration, "histogram", "Latency of the requests sent to the Form3 API.")
+
+	for _, key := range sortedKeys(r.durations) {
+		h := r.durations[key]
+		var cumulative uint64
+
+		for i, bound := range r.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", MetricRequestDuration, labels(key, formatFloat(bound)), cumulative)
+		}
+
+		fmt.Fprintf(w, "%s_bucket%s %d\n", MetricRequestDuration, labels(key, "+Inf"), h.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", MetricRequestDuration, labels(key, ""), formatFloat(h.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", MetricRequestDuration, labels(key, ""), h.count)
+	}
+
+	writeHeader(w, MetricRetriesTotal, "counter", "Requests to the Form3 API that were retried.")
+
+	for _, key := range sortedKeys(r.retries) {
+		fmt.Fprintf(w, "%s%s %d\n", MetricRetriesTotal, labels(key, ""), r.retries[key])
+	}
+
+	writeHeader(w, MetricInFlight, "gauge", "Requests to the Form3 API waiting for a response.")
+
+	for _, key := range sortedKeys(r.inFlight) {
+		fmt.Fprintf(w, "%s%s %d\n", MetricInFlight, labels(key, ""), r.inFlight[key])
+	}
+
+	return w.Flush()
+}
+
+func writeHeader(w *bufio.Writer, name string, kind string, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sortedKeys returns the keys of a map of series in a stable order
+func sortedKeys(series interface{}) []seriesKey {
+	var keys []seriesKey
+
+	switch series := series.(type) {
+	case map[seriesKey]uint64:
+		for key := range series {
+			keys = append(keys, key)
+		}
+	case map[seriesKey]int64:
+		for key := range series {
+			keys = append(keys, key)
+		}
+	case map[seriesKey]*histogram:
+		for key := range series {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+
+		if a.resource != b.resource {
+			return a.resource < b.resource
+		}
+
+		if a.operation != b.operation {
+			return a.operation < b.operation
+		}
+
+		return a.statusClass < b.statusClass
+	})
+
+	return keys
+}
+
+// labels formats the labels of a series, in alphabetical order
+func labels(key seriesKey, le string) string {
+	pairs := []string{}
+
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf(`le="%s"`, le))
+	}
+
+	pairs = append(pairs, fmt.Sprintf(`operation="%s"`, escapeLabel(key.operation)))
+	pairs = append(pairs, fmt.Sprintf(`resource="%s"`, escapeLabel(key.resource)))
+
+	if key.statusClass != "" {
+		pairs = append(pairs, fmt.Sprintf(`status_class="%s"`, escapeLabel(key.statusClass)))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces the value of a redacted field
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedFields are the fields of a payload that hold personal data
+var DefaultRedactedFields = []string{"name", "alternative_names", "account_number", "iban", "private_identification"}
+
+// Redactor masks the personal data of a body before it is logged
+type Redactor func(body []byte) string
+
+// NewFieldRedactor creates a Redactor that replaces the values of the given JSON fields with Redacted, at any
+// depth. A body that isn't JSON is not logged, as there is no telling what it holds
+func NewFieldRedactor(fields ...string) Redactor {
+	redacted := make(map[string]bool, len(fields))
+
+	for _, field := range fields {
+		redacted[field] = true
+	}
+
+	return func(body []byte) string {
+		if len(body) == 0 {
+			return ""
+		}
+
+		var payload interface{}
+
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return fmt.Sprintf("[%d bytes, not JSON]", len(body))
+		}
+
+		masked, err := json.Marshal(redact(payload, redacted))
+
+		if err != nil {
+			return fmt.Sprintf("[%d bytes]", len(body))
+		}
+
+		return string(masked)
+	}
+}
+
+// redact replaces the values of the redacted fields of a decoded JSON value
+func redact(value interface{}, redacted map[string]bool) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if redacted[key] {
+				value[key] = Redacted
+			} else {
+				value[key] = redact(field, redacted)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redact(item, redacted)
+		}
+	}
+
+	return value
+}
+
+// redactPath replaces the values of the filter[...] query parameters of a request path with Redacted. Filters
+// can hold personal data, like an IBAN or an account number
+func redactPath(path string) string {
+	i := strings.Index(path, "?")
+
+	if i < 0 {
+		return path
+	}
+
+	return path[:i+1] + redactQuery(path[i+1:])
+}
+
+// redactQuery masks a raw query the way redactPath does. The query is encoded again, sorted by name, so it can
+// be parsed back
+func redactQuery(rawQuery string) string {
+	query, err := url.ParseQuery(rawQuery)
+
+	if err != nil {
+		return Redacted
+	}
+
+	for key, values := range query {
+		if strings.HasPrefix(key, "filter[") {
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+	}
+
+	return query.Encode()
+}
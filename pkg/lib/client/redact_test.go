@@ -0,0 +1,28 @@
+package client_test
+
+import (
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewFieldRedactor(t *testing.T) {
+	redactor := client.NewFieldRedactor(client.DefaultRedactedFields...)
+
+	t.Run("should mask the personal data at any depth", func(t *testing.T) {
+		body := `{"data":[{"attributes":{"account_number":"41426819","alternative_names":["Sam Holder"],` +
+			`"bank_id":"400300","private_identification":{"birth_date":"2017-07-23"}}}]}`
+
+		assert.Equal(
+			t,
+			`{"data":[{"attributes":{"account_number":"[REDACTED]","alternative_names":"[REDACTED]",`+
+				`"bank_id":"400300","private_identification":"[REDACTED]"}}]}`,
+			redactor([]byte(body)),
+		)
+	})
+
+	t.Run("should not log a body that isn't JSON", func(t *testing.T) {
+		assert.Equal(t, "[9 bytes, not JSON]", redactor([]byte("Jane Doe,")))
+		assert.Equal(t, "", redactor(nil))
+	})
+}
@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy configures how Form3RestClient retries requests that failed with a transient error
+// (a connection error, a 429 or a 5xx response). GET requests and DELETE requests with a version are
+// retried. POST requests are only retried when RetryPost is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. A value lower than 2 disables retries
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It is doubled on every following retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts, including the delay requested by a Retry-After header.
+	// Zero means no cap
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay, between 0 and 1, that is randomised
+	Jitter float64
+	// RetryPost enables retries for POST requests. Only enable it when creating the same resource twice is safe
+	RetryPost bool
+}
+
+// DefaultRetryPolicy returns a policy that does up to 3 attempts with a 100ms base delay
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// NoRetryPolicy returns a policy that never retries
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+var (
+	jitterRand  = rand.New(rand.NewSource(time.Now().UnixNano()))
+	jitterMutex sync.Mutex
+)
+
+// allowsRetry reports whether a request can be retried
+func (p RetryPolicy) allowsRetry(req *Request) bool {
+	if p.MaxAttempts < 2 {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodDelete:
+		// A delete without a version is not safe to replay
+		u, err := url.Parse(req.Path)
+
+		return err == nil && u.Query().Get("version") != ""
+	case http.MethodPost:
+		return p.RetryPost
+	}
+
+	return false
+}
+
+// delay returns how long to wait after the given attempt failed
+func (p RetryPolicy) delay(attempt int, res *http.Response) time.Duration {
+	if retryAfter, ok := parseRetryAfter(res); ok {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return p.MaxDelay
+		}
+
+		return retryAfter
+	}
+
+	d := p.BaseDelay
+
+	// Stop doubling once the cap is reached to avoid overflowing
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		jitterMutex.Lock()
+		r := jitterRand.Float64()
+		jitterMutex.Unlock()
+
+		d -= time.Duration(float64(d) * p.Jitter * r)
+	}
+
+	return d
+}
+
+// shouldRetry reports whether the outcome of an attempt is a transient failure. err is the error of the
+// HTTP client, requests that can't be built or signed never reach it
+func shouldRetry(ctx context.Context, res *http.Response, err error) bool {
+	if err != nil {
+		// Connection errors are transient, but a cancelled or expired context is final
+		return ctx.Err() == nil
+	}
+
+	return res.StatusCode == http.StatusTooManyRequests ||
+		res.StatusCode == http.StatusInternalServerError ||
+		res.StatusCode == http.StatusBadGateway ||
+		res.StatusCode == http.StatusServiceUnavailable ||
+		res.StatusCode == http.StatusGatewayTimeout
+}
+
+// parseRetryAfter reads the Retry-After header, given either in seconds or as an HTTP date
+func parseRetryAfter(res *http.Response) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+
+	value := res.Header.Get("Retry-After")
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		d := time.Until(date)
+
+		if d < 0 {
+			d = 0
+		}
+
+		return d, true
+	}
+
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discardResponse drains and closes the body of a response that is not returned to the caller,
+// so the connection can be reused
+func discardResponse(res *http.Response) {
+	if res == nil || res.Body == nil {
+		return
+	}
+
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+}
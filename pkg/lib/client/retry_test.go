@@ -0,0 +1,333 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// Returns a response with the given status code and body
+func newResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		StatusCode: statusCode,
+		Header:     http.Header{},
+	}
+}
+
+// Returns a retry policy with short delays to keep tests fast
+func fastRetryPolicy() client.RetryPolicy {
+	return client.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}
+}
+
+func TestForm3RestClient_Retry(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+
+	t.Run("should retry a get on a 5xx response", func(t *testing.T) {
+		attempts := 0
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					attempts++
+
+					if attempts < 3 {
+						return newResponse(http.StatusServiceUnavailable, "unavailable"), nil
+					}
+
+					return newResponse(http.StatusOK, "A valid account"), nil
+				},
+			},
+			client.WithRetryPolicy(fastRetryPolicy()),
+		)
+
+		responseBody, err := form3Client.Get("path/to/form3/resource/endpoint")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "A valid account", string(responseBody))
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("should retry a get on a connection error", func(t *testing.T) {
+		attempts := 0
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					attempts++
+
+					if attempts == 1 {
+						return nil, errors.New("connection reset by peer")
+					}
+
+					return newResponse(http.StatusOK, "A valid account"), nil
+				},
+			},
+			client.WithRetryPolicy(fastRetryPolicy()),
+		)
+
+		_, err := form3Client.Get("path/to/form3/resource/endpoint")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("should return the last error when attempts are exhausted", func(t *testing.T) {
+		attempts := 0
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return newResponse(http.StatusTooManyRequests, "slow down"), nil
+				},
+			},
+			client.WithRetryPolicy(fastRetryPolicy()),
+		)
+
+		_, err := form3Client.Get("path/to/form3/resource/endpoint")
+
+		var apiErr *client.APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("should not retry client errors", func(t *testing.T) {
+		attempts := 0
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return newResponse(http.StatusNotFound, "not found"), nil
+				},
+			},
+			client.WithRetryPolicy(fastRetryPolicy()),
+		)
+
+		_, err := form3Client.Get("path/to/form3/resource/endpoint")
+
+		assert.True(t, client.IsNotFound(err))
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("should not retry by default", func(t *testing.T) {
+		attempts := 0
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return newResponse(http.StatusServiceUnavailable, "unavailable"), nil
+				},
+			},
+		)
+
+		_, err := form3Client.Get("path/to/form3/resource/endpoint")
+
+		assert.True(t, client.IsServerError(err))
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("should not retry a post unless enabled", func(t *testing.T) {
+		attempts := 0
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return newResponse(http.StatusServiceUnavailable, "unavailable"), nil
+				},
+			},
+			client.WithRetryPolicy(fastRetryPolicy()),
+		)
+
+		_, err := form3Client.Post("path/to/form3/resource/endpoint", []byte(`{"data":{}}`))
+
+		assert.True(t, client.IsServerError(err))
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("should send the idempotency key but not retry a post without RetryPost", func(t *testing.T) {
+		var keys []string
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					keys = append(keys, req.Header.Get(client.IdempotencyKeyHeader))
+					return newResponse(http.StatusServiceUnavailable, "unavailable"), nil
+				},
+			},
+			client.WithRetryPolicy(fastRetryPolicy()),
+		)
+
+		ctx := client.ContextWithIdempotencyKey(context.Background(), "my-key")
+		_, err := form3Client.PostWithContext(ctx, "path/to/form3/resource/endpoint", []byte(`{"data":{}}`))
+
+		assert.True(t, client.IsServerError(err))
+		assert.Equal(t, []string{"my-key"}, keys)
+	})
+
+	t.Run("should only send the idempotency key with a post", func(t *testing.T) {
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					assert.Empty(t, req.Header.Get(client.IdempotencyKeyHeader))
+					return newResponse(http.StatusOK, "{}"), nil
+				},
+			},
+		)
+
+		_, err := form3Client.GetWithContext(client.ContextWithIdempotencyKey(context.Background(), "my-key"), "path/to/form3/resource/endpoint")
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("should replay the body when retrying a post", func(t *testing.T) {
+		var bodies []string
+		policy := fastRetryPolicy()
+		policy.RetryPost = true
+
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					body, err := ioutil.ReadAll(req.Body)
+					require.NoError(t, err)
+					bodies = append(bodies, string(body))
+
+					if len(bodies) == 1 {
+						return newResponse(http.StatusBadGateway, "bad gateway"), nil
+					}
+
+					return newResponse(http.StatusCreated, "Account Created"), nil
+				},
+			},
+			client.WithRetryPolicy(policy),
+		)
+
+		responseBody, err := form3Client.Post("path/to/form3/resource/endpoint", []byte(`{"data":{}}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Account Created", string(responseBody))
+		assert.Equal(t, []string{`{"data":{}}`, `{"data":{}}`}, bodies)
+	})
+
+	t.Run("should only retry a delete with a version", func(t *testing.T) {
+		attempts := 0
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					return newResponse(http.StatusInternalServerError, "error"), nil
+				},
+			},
+			client.WithRetryPolicy(fastRetryPolicy()),
+		)
+
+		err := form3Client.Delete("path/to/form3/resource/endpoint")
+		assert.True(t, client.IsServerError(err))
+		assert.Equal(t, 1, attempts)
+
+		attempts = 0
+		err = form3Client.Delete("path/to/form3/resource/endpoint?version=1")
+		assert.True(t, client.IsServerError(err))
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("should honour the Retry-After header", func(t *testing.T) {
+		attempts := 0
+		policy := fastRetryPolicy()
+		policy.MaxDelay = 0
+
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					attempts++
+
+					if attempts == 1 {
+						res := newResponse(http.StatusTooManyRequests, "slow down")
+						res.Header.Set("Retry-After", "1")
+						return res, nil
+					}
+
+					return newResponse(http.StatusOK, "A valid account"), nil
+				},
+			},
+			client.WithRetryPolicy(policy),
+		)
+
+		start := time.Now()
+		_, err := form3Client.Get("path/to/form3/resource/endpoint")
+
+		assert.Nil(t, err)
+		assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("should cap the Retry-After header with the max delay", func(t *testing.T) {
+		attempts := 0
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					attempts++
+
+					if attempts == 1 {
+						res := newResponse(http.StatusTooManyRequests, "slow down")
+						res.Header.Set("Retry-After", "120")
+						return res, nil
+					}
+
+					return newResponse(http.StatusOK, "A valid account"), nil
+				},
+			},
+			client.WithRetryPolicy(fastRetryPolicy()),
+		)
+
+		start := time.Now()
+		_, err := form3Client.Get("path/to/form3/resource/endpoint")
+
+		assert.Nil(t, err)
+		assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("should stop retrying when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		policy := fastRetryPolicy()
+		policy.BaseDelay = time.Minute
+		policy.MaxDelay = time.Minute
+
+		form3Client := client.NewForm3RestClient(
+			baseURL,
+			&mockedHttpClient{
+				MockDo: func(req *http.Request) (*http.Response, error) {
+					cancel()
+					return newResponse(http.StatusServiceUnavailable, "unavailable"), nil
+				},
+			},
+			client.WithRetryPolicy(policy),
+		)
+
+		_, err := form3Client.GetWithContext(ctx, "path/to/form3/resource/endpoint")
+
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signer signs a request before it is sent. The body is passed along as the request body can only be read once
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// WithSigner sets the signer that signs every request, including every retry
+func WithSigner(signer Signer) RestClientOption {
+	return func(cl *Form3RestClient) {
+		cl.signer = signer
+	}
+}
+
+// HTTPSigner signs requests with HTTP Signatures. It sets the Date and Digest headers and a Signature header
+// covering the (request-target), host and date, plus the digest and content-length of requests with a body
+type HTTPSigner struct {
+	keyID     string
+	key       crypto.Signer
+	algorithm string
+	now       func() time.Time
+}
+
+// NewHTTPSigner creates an HTTPSigner for an RSA or ECDSA private key registered with the API under keyID
+func NewHTTPSigner(keyID string, key crypto.Signer) (*HTTPSigner, error) {
+	var algorithm string
+
+	switch key.Public().(type) {
+	case *rsa.PublicKey:
+		algorithm = "rsa-sha256"
+	case *ecdsa.PublicKey:
+		algorithm = "ecdsa-sha256"
+	default:
+		return nil, fmt.Errorf("form3: unsupported signing key type %T", key.Public())
+	}
+
+	if keyID == "" {
+		return nil, errors.New("form3: signing key ID is required")
+	}
+
+	return &HTTPSigner{
+		keyID:     keyID,
+		key:       key,
+		algorithm: algorithm,
+		now:       time.Now,
+	}, nil
+}
+
+// NewHTTPSignerFromFile creates an HTTPSigner for a PEM encoded private key file
+func NewHTTPSignerFromFile(keyID string, path string) (*HTTPSigner, error) {
+	key, err := LoadPrivateKey(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHTTPSigner(keyID, key)
+}
+
+// LoadPrivateKey reads a PEM encoded RSA or ECDSA private key, in PKCS #1, PKCS #8 or SEC 1 form
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	content, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("form3: reading private key: %w", err)
+	}
+
+	return ParsePrivateKeyPEM(content)
+}
+
+// ParsePrivateKeyPEM parses a PEM encoded RSA or ECDSA private key, in PKCS #1, PKCS #8 or SEC 1 form
+func ParsePrivateKeyPEM(content []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(content)
+
+	if block == nil {
+		return nil, errors.New("form3: no PEM block found in private key")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return key, nil
+		case *ecdsa.PrivateKey:
+			return key, nil
+		}
+
+		return nil, fmt.Errorf("form3: unsupported private key type %T", key)
+	}
+
+	return nil, fmt.Errorf("form3: unsupported PEM block type %q", block.Type)
+}
+
+// Sign sets the Date, Digest and Signature headers of a request
+func (s *HTTPSigner) Sign(req *http.Request, body []byte) error {
+	if req.Header.Get("Date") == "" {
+		req.Header.Set("Date", s.now().UTC().Format(http.TimeFormat))
+	}
+
+	host := req.Host
+
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	values := map[string]string{
+		"(request-target)": fmt.Sprintf("%s %s", strings.ToLower(req.Method), req.URL.RequestURI()),
+		"host":             host,
+		"date":             req.Header.Get("Date"),
+	}
+	headers := []string{"(request-target)", "host", "date"}
+
+	if len(body) > 0 {
+		req.Header.Set("Digest", Digest(body))
+		values["digest"] = req.Header.Get("Digest")
+		values["content-length"] = strconv.Itoa(len(body))
+		headers = append(headers, "digest", "content-length")
+	}
+
+	lines := make([]string, 0, len(headers))
+
+	for _, header := range headers {
+		lines = append(lines, fmt.Sprintf("%s: %s", header, values[header]))
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	signature, err := s.key.Sign(rand.Reader, hash[:], crypto.SHA256)
+
+	if err != nil {
+		return fmt.Errorf("form3: signing request: %w", err)
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(
+		`keyId="%s",algorithm="%s",headers="%s",signature="%s"`,
+		s.keyID,
+		s.algorithm,
+		strings.Join(headers, " "),
+		base64.StdEncoding.EncodeToString(signature),
+	))
+
+	return nil
+}
+
+// Digest returns the value of the Digest header of a body
+func Digest(body []byte) string {
+	hash := sha256.Sum256(body)
+
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(hash[:])
+}
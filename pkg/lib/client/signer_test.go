@@ -0,0 +1,217 @@
+package client_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Signature of the signing string of the POST request in TestHTTPSigner_Sign, generated with
+// openssl dgst -sha256 -sign testdata/rsa_private_key.pem
+const knownRSASignature = "mXFFnbIPEnueyxixouGDDGrRCVfD4dmjrJ41BUgHIj58dskXCNy+n7KYgbqnsWAe8h8E6qNfJjWc8Rs0YhieNoMqbZ7ktSoEkFOJkWfbKOAGuI2ejFTZSJFgNZFT7l84+/uTtfO3YUrXWH6X6swpZcU/IGck7TmKz3H4dwetAUaBxRH9ZMDizBdxMz/UAmS0jFTJ8D/1dR5TWCnf9lwMfR19oIcmfQMeYwdP2Rfiyl0I/xdcDpv+dW8onJp+/w1F7qYdz+O63HBoSToVQmslbJqZ4KPCymlBQeKQ2FXcQJ9bu3w5TQaP7btCTxMe6wO2/CFkg9P58IwcZ5HecZLe6w=="
+
+var signaturePattern = regexp.MustCompile(`^keyId="([^"]*)",algorithm="([^"]*)",headers="([^"]*)",signature="([^"]*)"$`)
+
+func TestDigest(t *testing.T) {
+	assert.Equal(t, "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=", client.Digest([]byte(`{"hello": "world"}`)))
+}
+
+func TestHTTPSigner_Sign(t *testing.T) {
+	t.Run("should match the known signature of a request with a body", func(t *testing.T) {
+		signer, err := client.NewHTTPSignerFromFile("my-key", "testdata/rsa_private_key.pem")
+		require.NoError(t, err)
+
+		body := []byte(`{"hello": "world"}`)
+		req, err := http.NewRequest(http.MethodPost, "https://api.form3.tech/v1/organisation/accounts/", bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Date", "Tue, 07 Jun 2014 20:51:35 GMT")
+
+		require.NoError(t, signer.Sign(req, body))
+
+		assert.Equal(t, "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=", req.Header.Get("Digest"))
+		assert.Equal(
+			t,
+			`keyId="my-key",algorithm="rsa-sha256",headers="(request-target) host date digest content-length",signature="`+knownRSASignature+`"`,
+			req.Header.Get("Signature"),
+		)
+	})
+
+	t.Run("should sign a request without a body and set the date", func(t *testing.T) {
+		signer, err := client.NewHTTPSignerFromFile("my-key", "testdata/pkcs8_private_key.pem")
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, "https://api.form3.tech/v1/organisation/accounts/?page[size]=10", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, signer.Sign(req, nil))
+
+		assert.NotEmpty(t, req.Header.Get("Date"))
+		assert.Empty(t, req.Header.Get("Digest"))
+
+		match := signaturePattern.FindStringSubmatch(req.Header.Get("Signature"))
+		require.NotNil(t, match)
+		assert.Equal(t, "(request-target) host date", match[3])
+	})
+
+	t.Run("should sign with an ECDSA key", func(t *testing.T) {
+		key, err := client.LoadPrivateKey("testdata/ec_private_key.pem")
+		require.NoError(t, err)
+		signer, err := client.NewHTTPSigner("ec-key", key)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodDelete, "https://api.form3.tech/v1/organisation/accounts/ad27e265?version=0", nil)
+		require.NoError(t, err)
+		req.Header.Set("Date", "Tue, 07 Jun 2014 20:51:35 GMT")
+
+		require.NoError(t, signer.Sign(req, nil))
+
+		match := signaturePattern.FindStringSubmatch(req.Header.Get("Signature"))
+		require.NotNil(t, match)
+		assert.Equal(t, "ec-key", match[1])
+		assert.Equal(t, "ecdsa-sha256", match[2])
+
+		signature, err := base64.StdEncoding.DecodeString(match[4])
+		require.NoError(t, err)
+
+		signingString := strings.Join([]string{
+			"(request-target): delete /v1/organisation/accounts/ad27e265?version=0",
+			"host: api.form3.tech",
+			"date: Tue, 07 Jun 2014 20:51:35 GMT",
+		}, "\n")
+		hash := sha256.Sum256([]byte(signingString))
+		assert.True(t, ecdsa.VerifyASN1(key.Public().(*ecdsa.PublicKey), hash[:], signature))
+	})
+}
+
+func TestNewHTTPSigner(t *testing.T) {
+	t.Run("should require a key ID", func(t *testing.T) {
+		_, err := client.NewHTTPSignerFromFile("", "testdata/rsa_private_key.pem")
+		assert.EqualError(t, err, "form3: signing key ID is required")
+	})
+
+	t.Run("should return an error if the key file doesn't exist", func(t *testing.T) {
+		_, err := client.NewHTTPSignerFromFile("my-key", "testdata/missing.pem")
+		assert.Error(t, err)
+	})
+
+	t.Run("should return an error if the key isn't PEM encoded", func(t *testing.T) {
+		_, err := client.ParsePrivateKeyPEM([]byte("not a key"))
+		assert.EqualError(t, err, "form3: no PEM block found in private key")
+	})
+
+	t.Run("should return an error for an unsupported PEM block", func(t *testing.T) {
+		_, err := client.ParsePrivateKeyPEM([]byte("-----BEGIN CERTIFICATE-----\nAAAA\n-----END CERTIFICATE-----\n"))
+		assert.EqualError(t, err, `form3: unsupported PEM block type "CERTIFICATE"`)
+	})
+}
+
+// Records the requests a signer is called with
+type recordingSigner struct {
+	bodies [][]byte
+}
+
+func (s *recordingSigner) Sign(req *http.Request, body []byte) error {
+	s.bodies = append(s.bodies, body)
+	req.Header.Set("Signature", "signed")
+	return nil
+}
+
+func TestForm3RestClient_WithSigner(t *testing.T) {
+	t.Run("should sign every attempt of a request", func(t *testing.T) {
+		signer := &recordingSigner{}
+		var signatures []string
+		responses := []*http.Response{newResponse(http.StatusServiceUnavailable, ""), newResponse(http.StatusCreated, "{}")}
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				signatures = append(signatures, req.Header.Get("Signature"))
+				res := responses[0]
+				responses = responses[1:]
+				return res, nil
+			},
+		}
+		policy := fastRetryPolicy()
+		policy.RetryPost = true
+
+		bu, _ := url.Parse("https://api.form3.tech/")
+		cl := client.NewForm3RestClient(bu, httpClient, client.WithRetryPolicy(policy), client.WithSigner(signer))
+
+		_, err := cl.Post("v1/organisation/accounts/", []byte(`{"hello": "world"}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"signed", "signed"}, signatures)
+		assert.Equal(t, [][]byte{[]byte(`{"hello": "world"}`), []byte(`{"hello": "world"}`)}, signer.bodies)
+	})
+
+	t.Run("should return the error of the signer", func(t *testing.T) {
+		key, err := client.LoadPrivateKey("testdata/rsa_private_key.pem")
+		require.NoError(t, err)
+		signer, err := client.NewHTTPSigner("my-key", failingKey{key})
+		require.NoError(t, err)
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				t.Fatal("an unsigned request was sent")
+				return nil, nil
+			},
+		}
+
+		bu, _ := url.Parse("https://api.form3.tech/")
+		cl := client.NewForm3RestClient(bu, httpClient, client.WithSigner(signer))
+
+		_, err = cl.Get("v1/organisation/accounts/")
+		assert.Error(t, err)
+	})
+
+	t.Run("should not retry a request that can't be signed", func(t *testing.T) {
+		signer := &countingSigner{err: errors.New("no key")}
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				t.Fatal("an unsigned request was sent")
+				return nil, nil
+			},
+		}
+
+		bu, _ := url.Parse("https://api.form3.tech/")
+		cl := client.NewForm3RestClient(bu, httpClient, client.WithSigner(signer), client.WithRetryPolicy(client.RetryPolicy{
+			MaxAttempts: 3,
+		}))
+
+		_, err := cl.Get("v1/organisation/accounts/")
+		assert.EqualError(t, err, "no key")
+		assert.Equal(t, 1, signer.calls)
+	})
+}
+
+// A signer that counts its calls and fails with err
+type countingSigner struct {
+	calls int
+	err   error
+}
+
+func (s *countingSigner) Sign(req *http.Request, body []byte) error {
+	s.calls++
+	return s.err
+}
+
+// A key that fails to sign
+type failingKey struct {
+	crypto.Signer
+}
+
+func (failingKey) Sign(_ io.Reader, _ []byte, _ crypto.SignerOpts) ([]byte, error) {
+	return nil, io.ErrUnexpectedEOF
+}
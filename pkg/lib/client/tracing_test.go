@@ -0,0 +1,71 @@
+package client_test
+
+import (
+	"context"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestForm3RestClient_WithTracer(t *testing.T) {
+	bu, _ := url.Parse("https://api.form3.tech/")
+
+	t.Run("should record a span for the request and propagate it", func(t *testing.T) {
+		exporter := tracing.NewInMemoryExporter()
+		var traceParents []string
+		statuses := []int{http.StatusServiceUnavailable, http.StatusNotFound}
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				traceParents = append(traceParents, req.Header.Get("traceparent"))
+				status := statuses[0]
+				statuses = statuses[1:]
+				return newResponse(status, ""), nil
+			},
+		}
+
+		cl := client.NewForm3RestClient(
+			bu,
+			httpClient,
+			client.WithRetryPolicy(fastRetryPolicy()),
+			client.WithTracer(tracing.NewTracer(exporter)),
+		)
+
+		_, err := cl.Get("v1/organisation/accounts/")
+		require.True(t, client.IsNotFound(err))
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "HTTP GET", spans[0].Name)
+		assert.Equal(t, "GET", spans[0].Attributes[tracing.AttributeHTTPMethod])
+		assert.Equal(t, "v1/organisation/accounts/", spans[0].Attributes[tracing.AttributeHTTPPath])
+		assert.Equal(t, http.StatusNotFound, spans[0].Attributes[tracing.AttributeHTTPStatus])
+		assert.Equal(t, 1, spans[0].Attributes[tracing.AttributeRetries])
+		assert.Error(t, spans[0].Err)
+		assert.Equal(t, []string{spans[0].SpanContext.TraceParent(), spans[0].SpanContext.TraceParent()}, traceParents)
+	})
+
+	t.Run("should propagate the span context of the caller without a tracer", func(t *testing.T) {
+		var traceParent string
+
+		httpClient := &mockedHttpClient{
+			MockDo: func(req *http.Request) (*http.Response, error) {
+				traceParent = req.Header.Get("traceparent")
+				return newResponse(http.StatusOK, "{}"), nil
+			},
+		}
+
+		remote, err := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		require.NoError(t, err)
+
+		cl := client.NewForm3RestClient(bu, httpClient)
+		_, err = cl.GetWithContext(tracing.ContextWithSpanContext(context.Background(), remote), "v1/organisation/accounts/")
+		require.NoError(t, err)
+
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceParent)
+	})
+}
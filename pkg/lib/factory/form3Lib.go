@@ -1,32 +1,154 @@
 package factory
 
 import (
+	"fmt"
+	"github.com/google/uuid"
 	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
 	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/organisations"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/resource"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
 	"net/http"
 	"net/url"
 )
 
+// DefaultAPIVersion is the API version prefix used when none is configured
+const DefaultAPIVersion = "v1"
+
 // StandardFactory abstracts the creation of instances.
 type StandardFactory interface {
 	BuildAccountsService(client.Form3ResourcesClient) accounts.Form3Accounts
-	BuildForm3Client(baseUrl url.URL) client.Form3ResourcesClient
+	BuildOrganisationsService(client.Form3ResourcesClient) organisations.Form3Organisations
+	BuildForm3Client(baseUrl *url.URL, opts ...client.RestClientOption) client.Form3ResourcesClient
 }
 
 // Form3LibFactory builds instances
-type Form3LibFactory struct{}
+type Form3LibFactory struct {
+	httpClient        client.HTTPClient
+	apiVersion        string
+	organisationID    uuid.UUID
+	interceptors      []client.Interceptor
+	tracer            tracing.Tracer
+	notFoundAsDeleted bool
+	validateAccounts  bool
+}
+
+// Option configures a Form3LibFactory
+type Option func(f *Form3LibFactory)
+
+// WithHTTPClient sets the HTTP client the Form3 clients are built with
+func WithHTTPClient(httpClient client.HTTPClient) Option {
+	return func(f *Form3LibFactory) {
+		f.httpClient = httpClient
+	}
+}
+
+// WithAPIVersion sets the API version prefix of the resource endpoints, e.g. v1
+func WithAPIVersion(apiVersion string) Option {
+	return func(f *Form3LibFactory) {
+		f.apiVersion = apiVersion
+	}
+}
+
+// WithOrganisationID sets the organisation ID the services use when a resource doesn't have one
+func WithOrganisationID(organisationID uuid.UUID) Option {
+	return func(f *Form3LibFactory) {
+		f.organisationID = organisationID
+	}
+}
+
+// WithInterceptors adds interceptors to every Form3 client built, ahead of the ones passed to BuildForm3Client
+func WithInterceptors(interceptors ...client.Interceptor) Option {
+	return func(f *Form3LibFactory) {
+		f.interceptors = append(f.interceptors, interceptors...)
+	}
+}
+
+// WithTracer sets the tracer of the services and the Form3 clients built
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(f *Form3LibFactory) {
+		f.tracer = tracer
+	}
+}
+
+// WithNotFoundAsDeleted makes the services treat the delete of a resource that doesn't exist as a success
+func WithNotFoundAsDeleted() Option {
+	return func(f *Form3LibFactory) {
+		f.notFoundAsDeleted = true
+	}
+}
+
+// WithAccountValidation makes the accounts services validate the accounts before creating them
+func WithAccountValidation() Option {
+	return func(f *Form3LibFactory) {
+		f.validateAccounts = true
+	}
+}
+
+// NewForm3LibFactory creates a Form3LibFactory. By default, clients use a new http.Client and endpoints
+// use the DefaultAPIVersion
+func NewForm3LibFactory(opts ...Option) *Form3LibFactory {
+	f := &Form3LibFactory{
+		apiVersion: DefaultAPIVersion,
+	}
 
-// NewForm3LibFactory creates a Form3LibFactory
-func NewForm3LibFactory() *Form3LibFactory {
-	return &Form3LibFactory{}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
 // BuildAccountsService builds a NewForm3AccountsService
 func (f *Form3LibFactory) BuildAccountsService(cl client.Form3ResourcesClient) accounts.Form3Accounts {
-	return accounts.NewForm3AccountsService(cl, "v1/organisation/accounts/")
+	opts := []accounts.ServiceOption{accounts.WithDefaultOrganisationID(f.organisationID)}
+
+	if f.tracer != nil {
+		opts = append(opts, accounts.WithTracer(f.tracer))
+	}
+
+	if f.notFoundAsDeleted {
+		opts = append(opts, accounts.WithNotFoundAsDeleted())
+	}
+
+	if f.validateAccounts {
+		opts = append(opts, accounts.WithValidation())
+	}
+
+	return accounts.NewForm3AccountsService(cl, fmt.Sprintf("%s/organisation/accounts/", f.apiVersion), opts...)
+}
+
+// BuildOrganisationsService builds a NewForm3OrganisationsService. The organisation ID is the parent of the
+// organisation units created without one
+func (f *Form3LibFactory) BuildOrganisationsService(cl client.Form3ResourcesClient) organisations.Form3Organisations {
+	opts := []resource.Option{resource.WithDefaultOrganisationID(f.organisationID)}
+
+	if f.tracer != nil {
+		opts = append(opts, resource.WithTracer(f.tracer))
+	}
+
+	if f.notFoundAsDeleted {
+		opts = append(opts, resource.WithNotFoundAsDeleted())
+	}
+
+	return organisations.NewForm3OrganisationsService(cl, fmt.Sprintf("%s/organisation/units/", f.apiVersion), opts...)
 }
 
-// BuildForm3Client build a NewForm3RestClient
-func (f *Form3LibFactory) BuildForm3Client(baseUrl *url.URL) client.Form3ResourcesClient {
-	return client.NewForm3RestClient(baseUrl, &http.Client{})
+// BuildForm3Client build a NewForm3RestClient. Options such as the retry policy are passed to the client
+func (f *Form3LibFactory) BuildForm3Client(baseUrl *url.URL, opts ...client.RestClientOption) client.Form3ResourcesClient {
+	httpClient := f.httpClient
+
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	if len(f.interceptors) > 0 {
+		opts = append([]client.RestClientOption{client.WithInterceptors(f.interceptors...)}, opts...)
+	}
+
+	if f.tracer != nil {
+		opts = append([]client.RestClientOption{client.WithTracer(f.tracer)}, opts...)
+	}
+
+	return client.NewForm3RestClient(baseUrl, httpClient, opts...)
 }
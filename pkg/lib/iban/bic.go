@@ -0,0 +1,47 @@
+package iban
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidBIC is returned when a BIC doesn't have the ISO 9362 structure
+var ErrInvalidBIC = errors.New("iban: invalid BIC")
+
+// BIC is a BIC broken down into its parts
+type BIC struct {
+	// BankCode is the 4 letter institution code
+	BankCode string
+	// Country is the ISO 3166 code of the country of the bank
+	Country string
+	// Location is the 2 character location code
+	Location string
+	// Branch is the 3 character branch code. Empty for an 8 character BIC, which is the primary office
+	Branch string
+}
+
+// ValidateBIC checks the ISO 9362 structure of a BIC: a 4 letter bank code, a 2 letter country code, a 2
+// character location code and an optional 3 character branch code
+func ValidateBIC(bic string) error {
+	_, err := ParseBIC(bic)
+	return err
+}
+
+// IsValidBIC reports whether a BIC passes ValidateBIC
+func IsValidBIC(bic string) bool {
+	return ValidateBIC(bic) == nil
+}
+
+// ParseBIC validates a BIC and breaks it down
+func ParseBIC(bic string) (*BIC, error) {
+	if (len(bic) != 8 && len(bic) != 11) || !isLetters(bic[:6]) || !isAlphanumeric(bic[6:]) {
+		return nil, fmt.Errorf("%w: %q must be 8 or 11 characters with a letter bank and country code", ErrInvalidBIC, bic)
+	}
+
+	return &BIC{
+		BankCode: bic[:4],
+		Country:  bic[4:6],
+		Location: bic[6:8],
+		Branch:   bic[8:],
+	}, nil
+}
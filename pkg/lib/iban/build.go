@@ -0,0 +1,202 @@
+package iban
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field is where a bank detail is in the BBAN of a country, as [Start, End) offsets, and which characters it has
+type Field struct {
+	Start int
+	End   int
+	// Alphanumeric tells whether the field can hold uppercase letters as well as digits
+	Alphanumeric bool
+}
+
+// Length returns the number of characters of the field. A country whose BBAN has no bank ID has a zero length one
+func (f Field) Length() int {
+	return f.End - f.Start
+}
+
+// Pattern returns the regular expression the values of the field match
+func (f Field) Pattern() string {
+	if f.Alphanumeric {
+		return fmt.Sprintf(`^[A-Z0-9]{%d}$`, f.Length())
+	}
+
+	return fmt.Sprintf(`^[0-9]{%d}$`, f.Length())
+}
+
+// matches reports whether a value fits the field
+func (f Field) matches(value string) bool {
+	if len(value) != f.Length() {
+		return false
+	}
+
+	if f.Alphanumeric {
+		return isAlphanumeric(value)
+	}
+
+	return isDigits(value)
+}
+
+// Layout is the national layout of the bank ID and the account number in the BBAN of a country. The bank ID is
+// the bank_id of a Form3 account and the account number its account_number
+type Layout struct {
+	BankID        Field
+	AccountNumber Field
+}
+
+// layouts are the BBAN layouts of the countries IBANs can be built and broken down for, as in the IBAN registry.
+// National check digits and the bank codes taken from the BIC are not part of either field
+var layouts = map[string]Layout{
+	"BE": {BankID: Field{Start: 0, End: 3}, AccountNumber: Field{Start: 3, End: 10}},
+	"CH": {BankID: Field{Start: 0, End: 5}, AccountNumber: Field{Start: 5, End: 17, Alphanumeric: true}},
+	"DE": {BankID: Field{Start: 0, End: 8}, AccountNumber: Field{Start: 8, End: 18}},
+	"ES": {BankID: Field{Start: 0, End: 8}, AccountNumber: Field{Start: 10, End: 20}},
+	"FR": {BankID: Field{Start: 0, End: 10}, AccountNumber: Field{Start: 10, End: 21, Alphanumeric: true}},
+	"GB": {BankID: Field{Start: 4, End: 10}, AccountNumber: Field{Start: 10, End: 18}},
+	"GR": {BankID: Field{Start: 0, End: 7}, AccountNumber: Field{Start: 7, End: 23, Alphanumeric: true}},
+	"IT": {BankID: Field{Start: 1, End: 11}, AccountNumber: Field{Start: 11, End: 23, Alphanumeric: true}},
+	"LU": {BankID: Field{Start: 0, End: 3}, AccountNumber: Field{Start: 3, End: 16, Alphanumeric: true}},
+	"NL": {AccountNumber: Field{Start: 4, End: 14}},
+	"PL": {BankID: Field{Start: 0, End: 8}, AccountNumber: Field{Start: 8, End: 24}},
+	"PT": {BankID: Field{Start: 0, End: 8}, AccountNumber: Field{Start: 8, End: 19}},
+}
+
+// LayoutOf returns the BBAN layout of a country. It reports false for the countries IBANs can't be built for
+func LayoutOf(country string) (Layout, bool) {
+	layout, ok := layouts[strings.ToUpper(country)]
+
+	return layout, ok
+}
+
+// Build builds the IBAN of an account from its bank details, as the country, bank_id, bic and account_number
+// attributes of a Form3 account. The bank details must fit the layout of the country. GB and NL IBANs take their
+// bank code from the BIC, the other countries don't need it. National check digits, e.g. the RIB key of FR or the
+// CIN of IT, are computed. Numeric account numbers shorter than the national format are padded with zeros
+func Build(country string, bankID string, bic string, accountNumber string) (string, error) {
+	country = strings.ToUpper(country)
+	bankID = Normalize(bankID)
+	accountNumber = Normalize(accountNumber)
+	layout, ok := layouts[country]
+
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedCountry, country)
+	}
+
+	accountLength := layout.AccountNumber.Length()
+
+	if isDigits(accountNumber) && len(accountNumber) < accountLength {
+		accountNumber = strings.Repeat("0", accountLength-len(accountNumber)) + accountNumber
+	}
+
+	if !layout.AccountNumber.matches(accountNumber) {
+		return "", fmt.Errorf("%w: the account number of a %s IBAN must match %s", ErrInvalidIBAN, country, layout.AccountNumber.Pattern())
+	}
+
+	switch {
+	case layout.BankID.Length() == 0 && bankID != "":
+		return "", fmt.Errorf("%w: a %s IBAN has no bank ID", ErrInvalidIBAN, country)
+	case layout.BankID.Length() > 0 && !layout.BankID.matches(bankID):
+		return "", fmt.Errorf("%w: the bank ID of a %s IBAN must match %s", ErrInvalidIBAN, country, layout.BankID.Pattern())
+	}
+
+	var bban string
+
+	switch country {
+	case "GB", "NL":
+		if err := ValidateBIC(bic); err != nil {
+			return "", fmt.Errorf("%w: a %s IBAN needs the BIC of the bank: %v", ErrInvalidIBAN, country, err)
+		}
+
+		bban = Normalize(bic)[:4] + bankID + accountNumber
+	case "BE":
+		bban = bankID + accountNumber + fmt.Sprintf("%02d", checkMod97(bankID+accountNumber))
+	case "ES":
+		bban = bankID + spanishControlDigits(bankID, accountNumber) + accountNumber
+	case "FR":
+		bban = bankID + accountNumber + fmt.Sprintf("%02d", 97-mod97(frenchDigits(bankID+accountNumber)+"00"))
+	case "IT":
+		bban = italianCIN(bankID+accountNumber) + bankID + accountNumber
+	case "PT":
+		bban = bankID + accountNumber + fmt.Sprintf("%02d", 98-mod97(bankID+accountNumber+"00"))
+	default:
+		bban = bankID + accountNumber
+	}
+
+	return New(country, bban)
+}
+
+// checkMod97 returns the remainder of the division by 97 of a number, with 97 instead of 0 as Belgian check
+// digits do
+func checkMod97(digits string) int {
+	if remainder := mod97(digits); remainder != 0 {
+		return remainder
+	}
+
+	return 97
+}
+
+// spanishControlDigits computes the two control digits of a Spanish BBAN, the first one over the bank and branch
+// and the second one over the account number
+func spanishControlDigits(bankID string, accountNumber string) string {
+	weights := []int{1, 2, 4, 8, 5, 10, 9, 7, 3, 6}
+	digit := func(value string) int {
+		sum := 0
+
+		for i, c := range value {
+			sum += int(c-'0') * weights[i]
+		}
+
+		switch control := 11 - sum%11; control {
+		case 11:
+			return 0
+		case 10:
+			return 1
+		default:
+			return control
+		}
+	}
+
+	return fmt.Sprintf("%d%d", digit("00"+bankID), digit(accountNumber))
+}
+
+// frenchLetterDigits are the digits of the letters A to Z in a French bank detail
+const frenchLetterDigits = "12345678912345678923456789"
+
+// frenchDigits replaces the letters of a French bank detail with the digits the RIB key is computed with
+func frenchDigits(value string) string {
+	return strings.Map(func(c rune) rune {
+		if c >= 'A' && c <= 'Z' {
+			return rune(frenchLetterDigits[c-'A'])
+		}
+
+		return c
+	}, value)
+}
+
+// italianOddValues are the values of the digits 0 to 9 and of the letters A to Z at the odd positions of an
+// Italian bank detail. A digit has the value of the letter at the same index
+var italianOddValues = []int{1, 0, 5, 7, 9, 13, 15, 17, 19, 21, 2, 4, 18, 20, 11, 3, 6, 8, 12, 14, 16, 10, 22, 25, 24, 23}
+
+// italianCIN computes the CIN, the check letter of an Italian BBAN, over the ABI, CAB and account number
+func italianCIN(value string) string {
+	sum := 0
+
+	for i, c := range value {
+		index := int(c - 'A')
+
+		if c >= '0' && c <= '9' {
+			index = int(c - '0')
+		}
+
+		if i%2 == 0 {
+			sum += italianOddValues[index]
+		} else {
+			sum += index
+		}
+	}
+
+	return string(rune('A' + sum%26))
+}
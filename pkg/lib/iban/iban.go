@@ -0,0 +1,166 @@
+// Package iban validates, formats, parses and builds IBANs, and validates the BICs accounts are paired with.
+// It works on plain strings, the accounts package applies it to the attributes of an account
+package iban
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidIBAN is returned when an IBAN has an invalid format, length or checksum
+	ErrInvalidIBAN = errors.New("iban: invalid IBAN")
+	// ErrUnsupportedCountry is returned when an IBAN can't be built or broken down for a country
+	ErrUnsupportedCountry = errors.New("iban: unsupported country")
+)
+
+// lengths are the lengths of the IBANs of the countries of the IBAN registry
+var lengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BR": 29,
+	"BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DK": 18, "DO": 28, "EE": 20, "EG": 29,
+	"ES": 24, "FI": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28,
+	"HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20,
+	"LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "MC": 27, "MD": 24, "ME": 22, "MK": 19,
+	"MR": 27, "MT": 31, "MU": 30, "NL": 18, "NO": 15, "PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29,
+	"RO": 24, "RS": 22, "SA": 24, "SC": 31, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20,
+}
+
+// IBAN is an IBAN broken down into its parts
+type IBAN struct {
+	// Country is the ISO 3166 code of the country, e.g. GB
+	Country string
+	// CheckDigits are the two mod-97 check digits
+	CheckDigits string
+	// BBAN is the country specific part
+	BBAN string
+	// BankID is the national bank code, as the bank_id of an account, e.g. the sort code of a GB IBAN. Only set
+	// for the countries an IBAN can be built for
+	BankID string
+	// AccountNumber is the account number within the bank. Only set for the countries an IBAN can be built for
+	AccountNumber string
+}
+
+// String returns the IBAN in electronic form
+func (i *IBAN) String() string {
+	return i.Country + i.CheckDigits + i.BBAN
+}
+
+// Normalize returns the electronic form of an IBAN: upper case, without spaces
+func Normalize(iban string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+}
+
+// Format returns the print form of an IBAN: upper case, in groups of four characters
+//
+//	iban.Format("gb29nwbk60161331926819") // GB29 NWBK 6016 1331 9268 19
+func Format(iban string) string {
+	electronic := Normalize(iban)
+	var groups []string
+
+	for len(electronic) > 4 {
+		groups = append(groups, electronic[:4])
+		electronic = electronic[4:]
+	}
+
+	return strings.Join(append(groups, electronic), " ")
+}
+
+// Validate checks the country, the length and the mod-97 checksum of an IBAN, in electronic or print form
+func Validate(iban string) error {
+	electronic := Normalize(iban)
+
+	if len(electronic) < 5 || !isLetters(electronic[:2]) || !isDigits(electronic[2:4]) || !isAlphanumeric(electronic[4:]) {
+		return fmt.Errorf("%w: %q is not an IBAN", ErrInvalidIBAN, iban)
+	}
+
+	length, ok := lengths[electronic[:2]]
+
+	if !ok {
+		return fmt.Errorf("%w: %q has an unknown country", ErrInvalidIBAN, iban)
+	}
+
+	if len(electronic) != length {
+		return fmt.Errorf("%w: %q must have %d characters", ErrInvalidIBAN, iban, length)
+	}
+
+	if mod97(electronic[4:]+electronic[:4]) != 1 {
+		return fmt.Errorf("%w: %q has an invalid checksum", ErrInvalidIBAN, iban)
+	}
+
+	return nil
+}
+
+// IsValid reports whether an IBAN passes Validate
+func IsValid(iban string) bool {
+	return Validate(iban) == nil
+}
+
+// Parse validates an IBAN and breaks it down. The bank ID and account number are only derived for the countries
+// an IBAN can be built for
+func Parse(iban string) (*IBAN, error) {
+	if err := Validate(iban); err != nil {
+		return nil, err
+	}
+
+	electronic := Normalize(iban)
+	parsed := &IBAN{
+		Country:     electronic[:2],
+		CheckDigits: electronic[2:4],
+		BBAN:        electronic[4:],
+	}
+
+	if layout, ok := layouts[parsed.Country]; ok {
+		parsed.BankID = parsed.BBAN[layout.BankID.Start:layout.BankID.End]
+		parsed.AccountNumber = parsed.BBAN[layout.AccountNumber.Start:layout.AccountNumber.End]
+	}
+
+	return parsed, nil
+}
+
+// New builds an IBAN from its country and BBAN by computing the check digits
+func New(country string, bban string) (string, error) {
+	country = strings.ToUpper(country)
+	iban := country + checkDigits(country, Normalize(bban)) + Normalize(bban)
+
+	if err := Validate(iban); err != nil {
+		return "", err
+	}
+
+	return iban, nil
+}
+
+// checkDigits computes the mod-97 check digits of an IBAN
+func checkDigits(country string, bban string) string {
+	return fmt.Sprintf("%02d", 98-mod97(bban+country+"00"))
+}
+
+// mod97 returns the remainder of the division by 97 of a number written with digits and letters, where letters
+// count as two digits from A = 10 to Z = 35
+func mod97(value string) int {
+	remainder := 0
+
+	for _, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		}
+	}
+
+	return remainder
+}
+
+func isLetters(value string) bool {
+	return strings.IndexFunc(value, func(c rune) bool { return c < 'A' || c > 'Z' }) < 0
+}
+
+func isDigits(value string) bool {
+	return strings.IndexFunc(value, func(c rune) bool { return c < '0' || c > '9' }) < 0
+}
+
+func isAlphanumeric(value string) bool {
+	return strings.IndexFunc(value, func(c rune) bool { return (c < 'A' || c > 'Z') && (c < '0' || c > '9') }) < 0
+}
@@ -0,0 +1,153 @@
+package iban_test
+
+import (
+	"errors"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/iban"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// Examples of the IBAN registry with the bank details of a Form3 account
+var examples = []struct {
+	iban          string
+	bankID        string
+	bic           string
+	accountNumber string
+}{
+	{"BE68539007547034", "539", "", "0075470"},
+	{"CH9300762011623852957", "00762", "", "011623852957"},
+	{"DE89370400440532013000", "37040044", "", "0532013000"},
+	{"ES9121000418450200051332", "21000418", "", "0200051332"},
+	{"FR1420041010050500013M02606", "2004101005", "", "0500013M026"},
+	{"GB29NWBK60161331926819", "601613", "NWBKGB2L", "31926819"},
+	{"GR1601101250000000012300695", "0110125", "", "0000000012300695"},
+	{"IT60X0542811101000000123456", "0542811101", "", "000000123456"},
+	{"LU280019400644750000", "001", "", "9400644750000"},
+	{"NL91ABNA0417164300", "", "ABNANL2A", "0417164300"},
+	{"PL61109010140000071219812874", "10901014", "", "0000071219812874"},
+	{"PT50000201231234567890154", "00020123", "", "12345678901"},
+}
+
+func TestValidate(t *testing.T) {
+
+	t.Run("should accept valid IBANs in electronic and print form", func(t *testing.T) {
+		for _, example := range examples {
+			assert.Nil(t, iban.Validate(example.iban), example.iban)
+		}
+
+		assert.True(t, iban.IsValid("GB29 NWBK 6016 1331 9268 19"))
+		assert.True(t, iban.IsValid("no9386011117947"))
+	})
+
+	t.Run("should reject invalid IBANs", func(t *testing.T) {
+		for _, value := range []string{
+			"",
+			"GB29",
+			"GB28NWBK60161331926819",
+			"GB29NWBK6016133192681",
+			"XX29NWBK60161331926819",
+			"GB2XNWBK60161331926819",
+			"GB29NWBK6016133192681!",
+		} {
+			err := iban.Validate(value)
+			assert.True(t, errors.Is(err, iban.ErrInvalidIBAN), value)
+		}
+	})
+}
+
+func TestFormat(t *testing.T) {
+
+	t.Run("should convert between the electronic and print forms", func(t *testing.T) {
+		assert.Equal(t, "GB29 NWBK 6016 1331 9268 19", iban.Format("gb29nwbk60161331926819"))
+		assert.Equal(t, "NL91 ABNA 0417 1643 00", iban.Format("NL91ABNA0417164300"))
+		assert.Equal(t, "GB29NWBK60161331926819", iban.Normalize(" gb29 NWBK 6016 1331 9268 19 "))
+	})
+}
+
+func TestParse(t *testing.T) {
+
+	t.Run("should derive the bank details of the supported countries", func(t *testing.T) {
+		for _, example := range examples {
+			parsed, err := iban.Parse(example.iban)
+			require.NoError(t, err, example.iban)
+
+			assert.Equal(t, example.iban[:2], parsed.Country)
+			assert.Equal(t, example.iban[2:4], parsed.CheckDigits)
+			assert.Equal(t, example.bankID, parsed.BankID, example.iban)
+			assert.Equal(t, example.accountNumber, parsed.AccountNumber, example.iban)
+			assert.Equal(t, example.iban, parsed.String())
+		}
+	})
+
+	t.Run("should only split the BBAN of other countries", func(t *testing.T) {
+		parsed, err := iban.Parse("NO93 8601 1117 947")
+		require.NoError(t, err)
+
+		assert.Equal(t, &iban.IBAN{Country: "NO", CheckDigits: "93", BBAN: "86011117947"}, parsed)
+	})
+
+	t.Run("should reject an invalid IBAN", func(t *testing.T) {
+		_, err := iban.Parse("GB28NWBK60161331926819")
+
+		assert.True(t, errors.Is(err, iban.ErrInvalidIBAN))
+	})
+}
+
+func TestBuild(t *testing.T) {
+
+	t.Run("should build the IBANs of the supported countries", func(t *testing.T) {
+		for _, example := range examples {
+			built, err := iban.Build(example.iban[:2], example.bankID, example.bic, example.accountNumber)
+
+			assert.Nil(t, err, example.iban)
+			assert.Equal(t, example.iban, built)
+		}
+	})
+
+	t.Run("should pad short numeric account numbers", func(t *testing.T) {
+		built, err := iban.Build("DE", "37040044", "", "532013000")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "DE89370400440532013000", built)
+	})
+
+	t.Run("should need the BIC of a GB account", func(t *testing.T) {
+		_, err := iban.Build("GB", "601613", "", "31926819")
+
+		assert.True(t, errors.Is(err, iban.ErrInvalidIBAN))
+	})
+
+	t.Run("should reject bank details of the wrong length", func(t *testing.T) {
+		_, err := iban.Build("GB", "60161", "NWBKGB2L", "31926819")
+
+		assert.True(t, errors.Is(err, iban.ErrInvalidIBAN))
+	})
+
+	t.Run("should reject an unsupported country", func(t *testing.T) {
+		_, err := iban.Build("US", "021000021", "CHASUS33", "123456789")
+
+		assert.True(t, errors.Is(err, iban.ErrUnsupportedCountry))
+	})
+}
+
+func TestParseBIC(t *testing.T) {
+
+	t.Run("should break down 8 and 11 character BICs", func(t *testing.T) {
+		bic, err := iban.ParseBIC("NWBKGB2L")
+		require.NoError(t, err)
+		assert.Equal(t, &iban.BIC{BankCode: "NWBK", Country: "GB", Location: "2L"}, bic)
+
+		bic, err = iban.ParseBIC("DEUTDEFF500")
+		require.NoError(t, err)
+		assert.Equal(t, "500", bic.Branch)
+	})
+
+	t.Run("should reject invalid BICs", func(t *testing.T) {
+		for _, value := range []string{"", "NWBKGB2", "NWBKGB2L50", "NW1KGB2L", "nwbkgb2l", "NWBKGB2L-00"} {
+			assert.True(t, errors.Is(iban.ValidateBIC(value), iban.ErrInvalidBIC), value)
+		}
+
+		assert.False(t, iban.IsValidBIC("NWBK GB2L"))
+	})
+}
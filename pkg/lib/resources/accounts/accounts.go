@@ -1,91 +1,310 @@
 package accounts
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"github.com/google/uuid"
 	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/resource"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
 	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
 )
 
+var (
+	// ErrMissingAccountID is returned by CreateOrGet when the account has no client-generated ID
+	ErrMissingAccountID = resource.ErrMissingID
+	// ErrInvalidVersion is returned by Delete and Update when the version is negative
+	ErrInvalidVersion = resource.ErrInvalidVersion
+)
+
 // Defines the Accounts interface
 type Form3Accounts interface {
 	Fetch(accountID uuid.UUID) (*model.AccountApiResponse, error)
 	Delete(accountID uuid.UUID, version int) error
 	Create(account *model.AccountCreateRequest) (*model.AccountApiResponse, error)
+	FetchWithContext(ctx context.Context, accountID uuid.UUID) (*model.AccountApiResponse, error)
+	DeleteWithContext(ctx context.Context, accountID uuid.UUID, version int) error
+	DeleteLatest(accountID uuid.UUID, maxAttempts int) error
+	DeleteLatestWithContext(ctx context.Context, accountID uuid.UUID, maxAttempts int) error
+	CreateWithContext(ctx context.Context, account *model.AccountCreateRequest) (*model.AccountApiResponse, error)
+	CreateOrGet(account *model.AccountCreateRequest) (*model.AccountApiResponse, bool, error)
+	CreateOrGetWithContext(ctx context.Context, account *model.AccountCreateRequest) (*model.AccountApiResponse, bool, error)
+	Update(account *model.AccountUpdateRequest) (*model.AccountApiResponse, error)
+	UpdateWithContext(ctx context.Context, account *model.AccountUpdateRequest) (*model.AccountApiResponse, error)
+	Mutate(accountID uuid.UUID, maxAttempts int, mutate MutateFunc) (*model.AccountApiResponse, error)
+	MutateWithContext(ctx context.Context, accountID uuid.UUID, maxAttempts int, mutate MutateFunc) (*model.AccountApiResponse, error)
+	List(opts *ListOptions) (*model.AccountListResponse, error)
+	ListWithContext(ctx context.Context, opts *ListOptions) (*model.AccountListResponse, error)
+	ListAll(opts *ListOptions) *AccountIterator
+	ListAllWithContext(ctx context.Context, opts *ListOptions) *AccountIterator
 }
 
+// MutateFunc applies changes to an account before it is sent in an update. Returning an error aborts the update
+type MutateFunc func(account *model.Account) error
+
 // Form3AccountsService implements the Accounts interface. This service is meant to be the lib API
-// and handle any logic around Accounts
+// and handle any logic around Accounts. The requests are done by a resource.Endpoint
 type Form3AccountsService struct {
-	client           client.Form3ResourcesClient
-	accountsEndpoint string
+	endpoint              *resource.Endpoint
+	defaultOrganisationID uuid.UUID
+	tracer                tracing.Tracer
+	notFoundAsDeleted     bool
+	validate              bool
+}
+
+// ServiceOption configures optional behaviour of a Form3AccountsService
+type ServiceOption func(f3a *Form3AccountsService)
+
+// WithDefaultOrganisationID sets the organisation ID of the accounts created without one
+func WithDefaultOrganisationID(organisationID uuid.UUID) ServiceOption {
+	return func(f3a *Form3AccountsService) {
+		f3a.defaultOrganisationID = organisationID
+	}
+}
+
+// WithTracer sets the tracer that records a span for every operation. The spans of the requests are children
+// of it when the client uses the same tracer
+func WithTracer(tracer tracing.Tracer) ServiceOption {
+	return func(f3a *Form3AccountsService) {
+		f3a.tracer = tracer
+	}
+}
+
+// WithNotFoundAsDeleted makes the deletes of an account that doesn't exist succeed, e.g. when an earlier attempt
+// already deleted it
+func WithNotFoundAsDeleted() ServiceOption {
+	return func(f3a *Form3AccountsService) {
+		f3a.notFoundAsDeleted = true
+	}
+}
+
+// WithValidation makes Create and CreateOrGet check the account with ValidateCreateRequest before it is sent, so
+// an invalid account fails without a round trip to the API
+func WithValidation() ServiceOption {
+	return func(f3a *Form3AccountsService) {
+		f3a.validate = true
+	}
 }
 
 // NewForm3AccountsService creates a Form3AccountsService
-func NewForm3AccountsService(cl client.Form3ResourcesClient, ae string) *Form3AccountsService {
-	return &Form3AccountsService{
-		client:           cl,
-		accountsEndpoint: ae,
+func NewForm3AccountsService(cl client.Form3ResourcesClient, ae string, opts ...ServiceOption) *Form3AccountsService {
+	f3a := &Form3AccountsService{
+		tracer: tracing.NoopTracer(),
+	}
+
+	for _, opt := range opts {
+		opt(f3a)
 	}
+
+	endpointOpts := []resource.Option{
+		resource.WithTracer(f3a.tracer),
+		resource.WithIDAttribute(tracing.AttributeAccountID),
+	}
+
+	if f3a.notFoundAsDeleted {
+		endpointOpts = append(endpointOpts, resource.WithNotFoundAsDeleted())
+	}
+
+	f3a.endpoint = resource.NewEndpoint(cl, "accounts", ae, endpointOpts...)
+
+	return f3a
 }
 
 // Fetch is used to retrieve Form3 Accounts
 func (f3a *Form3AccountsService) Fetch(accountID uuid.UUID) (*model.AccountApiResponse, error) {
-	path := fmt.Sprintf(
-		"%s%s",
-		f3a.accountsEndpoint,
-		accountID.String(),
-	)
-	responseBody, err := f3a.client.Get(path)
-
-	if err != nil {
-		return nil, err
-	}
+	return f3a.FetchWithContext(context.Background(), accountID)
+}
 
-	var accountsResponse model.AccountApiResponse
-	err = json.Unmarshal(responseBody, &accountsResponse)
+// FetchWithContext is used to retrieve Form3 Accounts. The request is bound to ctx
+func (f3a *Form3AccountsService) FetchWithContext(ctx context.Context, accountID uuid.UUID) (*model.AccountApiResponse, error) {
+	var response model.AccountApiResponse
 
-	if err != nil {
+	if err := f3a.endpoint.Fetch(ctx, accountID, &response); err != nil {
 		return nil, err
 	}
 
-	return &accountsResponse, nil
+	return &response, nil
 }
 
-// Delete is used to delete Form3 Accounts
+// Delete is used to delete Form3 Accounts. The version must be the current one, otherwise the API responds with
+// a conflict that can be checked with client.IsConflict
 func (f3a *Form3AccountsService) Delete(accountID uuid.UUID, version int) error {
-	path := fmt.Sprintf(
-		"%s%s?version=%b",
-		f3a.accountsEndpoint,
-		accountID.String(),
-		version,
-	)
-	err := f3a.client.Delete(path)
+	return f3a.DeleteWithContext(context.Background(), accountID, version)
+}
+
+// DeleteWithContext is used to delete Form3 Accounts. The request is bound to ctx
+func (f3a *Form3AccountsService) DeleteWithContext(ctx context.Context, accountID uuid.UUID, version int) error {
+	return f3a.endpoint.Delete(ctx, accountID, model.Version(version))
+}
+
+// DeleteLatest fetches the current version of an account and deletes it. If the delete fails with a version
+// conflict, the account is fetched again, up to maxAttempts times in total
+func (f3a *Form3AccountsService) DeleteLatest(accountID uuid.UUID, maxAttempts int) error {
+	return f3a.DeleteLatestWithContext(context.Background(), accountID, maxAttempts)
+}
 
-	return err
+// DeleteLatestWithContext is like DeleteLatest. Every request is bound to ctx
+func (f3a *Form3AccountsService) DeleteLatestWithContext(ctx context.Context, accountID uuid.UUID, maxAttempts int) error {
+	return f3a.endpoint.DeleteLatest(ctx, accountID, maxAttempts)
 }
 
-// Create is used to create Form3 Accounts
+// Create is used to create Form3 Accounts. If an account with the same ID and the same attributes already exists,
+// e.g. because a previous attempt timed out after the account was created, it is fetched and returned
 func (f3a *Form3AccountsService) Create(account *model.AccountCreateRequest) (*model.AccountApiResponse, error) {
-	jsonBody, err := json.Marshal(account)
+	return f3a.CreateWithContext(context.Background(), account)
+}
+
+// CreateWithContext is used to create Form3 Accounts. The request is bound to ctx
+func (f3a *Form3AccountsService) CreateWithContext(ctx context.Context, account *model.AccountCreateRequest) (*model.AccountApiResponse, error) {
+	account, err := f3a.prepare(account)
 
 	if err != nil {
 		return nil, err
 	}
 
-	responseBody, err := f3a.client.Post(f3a.accountsEndpoint, jsonBody)
+	var response model.AccountApiResponse
 
-	if err != nil {
+	if err := f3a.endpoint.Create(ctx, account.Data.ID, account, &response); err != nil {
 		return nil, err
 	}
 
-	var accountsResponse model.AccountApiResponse
-	err = json.Unmarshal(responseBody, &accountsResponse)
+	return &response, nil
+}
+
+// CreateOrGet creates an account or, if an account with the same ID already exists, fetches it whatever its
+// attributes. The returned bool reports whether the account was created. The account must have an ID
+func (f3a *Form3AccountsService) CreateOrGet(account *model.AccountCreateRequest) (*model.AccountApiResponse, bool, error) {
+	return f3a.CreateOrGetWithContext(context.Background(), account)
+}
+
+// CreateOrGetWithContext is like CreateOrGet. Every request is bound to ctx
+func (f3a *Form3AccountsService) CreateOrGetWithContext(ctx context.Context, account *model.AccountCreateRequest) (*model.AccountApiResponse, bool, error) {
+	if account.Data.ID == uuid.Nil {
+		return nil, false, ErrMissingAccountID
+	}
+
+	account, err := f3a.prepare(account)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var response model.AccountApiResponse
+	created, err := f3a.endpoint.CreateOrFetch(ctx, account.Data.ID, account, &response)
 
 	if err != nil {
+		return nil, false, err
+	}
+
+	return &response, created, nil
+}
+
+// prepare sets the default organisation of an account to be created, if it has none, and validates it when
+// the service validates accounts. The account of the caller is left untouched
+func (f3a *Form3AccountsService) prepare(account *model.AccountCreateRequest) (*model.AccountCreateRequest, error) {
+	if account.Data.OrganisationID == uuid.Nil && f3a.defaultOrganisationID != uuid.Nil {
+		withOrganisation := *account
+		withOrganisation.Data.OrganisationID = f3a.defaultOrganisationID
+		account = &withOrganisation
+	}
+
+	if f3a.validate {
+		if err := ValidateCreateRequest(account); err != nil {
+			return nil, err
+		}
+	}
+
+	return account, nil
+}
+
+// Update is used to update Form3 Accounts. The version of the account must be the current one, otherwise
+// the API responds with a conflict that can be checked with client.IsConflict
+func (f3a *Form3AccountsService) Update(account *model.AccountUpdateRequest) (*model.AccountApiResponse, error) {
+	return f3a.UpdateWithContext(context.Background(), account)
+}
+
+// UpdateWithContext is used to update Form3 Accounts. The request is bound to ctx
+func (f3a *Form3AccountsService) UpdateWithContext(ctx context.Context, account *model.AccountUpdateRequest) (*model.AccountApiResponse, error) {
+	var response model.AccountApiResponse
+
+	if err := f3a.endpoint.Update(ctx, account.Data.ID, account.Data.Version, account, &response); err != nil {
 		return nil, err
 	}
 
-	return &accountsResponse, nil
+	return &response, nil
+}
+
+// Mutate fetches an account, applies mutate to it and updates it. If the update fails with a version conflict,
+// the account is fetched again and mutate is re-applied, up to maxAttempts times in total
+func (f3a *Form3AccountsService) Mutate(accountID uuid.UUID, maxAttempts int, mutate MutateFunc) (*model.AccountApiResponse, error) {
+	return f3a.MutateWithContext(context.Background(), accountID, maxAttempts, mutate)
+}
+
+// MutateWithContext is like Mutate. Every request is bound to ctx
+func (f3a *Form3AccountsService) MutateWithContext(ctx context.Context, accountID uuid.UUID, maxAttempts int, mutate MutateFunc) (*model.AccountApiResponse, error) {
+	ctx, span := f3a.endpoint.StartSpan(ctx, "mutate", accountID)
+	response, attempts, err := f3a.mutate(ctx, accountID, maxAttempts, mutate)
+	span.SetAttribute(tracing.AttributeAttempts, attempts)
+	resource.EndSpan(span, err)
+
+	return response, err
+}
+
+// mutate does the fetch and update cycles of MutateWithContext and returns how many there were
+func (f3a *Form3AccountsService) mutate(ctx context.Context, accountID uuid.UUID, maxAttempts int, mutate MutateFunc) (*model.AccountApiResponse, int, error) {
+	for attempt := 1; ; attempt++ {
+		current, err := f3a.FetchWithContext(ctx, accountID)
+
+		if err != nil {
+			return nil, attempt, err
+		}
+
+		account := current.Data
+
+		if err := mutate(&account); err != nil {
+			return nil, attempt, err
+		}
+
+		// The version is the one that was fetched, whatever mutate did with it
+		account.ID = accountID
+		account.Version = current.Data.Version
+		response, err := f3a.UpdateWithContext(ctx, &model.AccountUpdateRequest{Data: account})
+
+		if err == nil || !client.IsConflict(err) || attempt >= maxAttempts {
+			return response, attempt, err
+		}
+	}
+}
+
+// List is used to retrieve a page of Form3 Accounts, optionally filtered
+func (f3a *Form3AccountsService) List(opts *ListOptions) (*model.AccountListResponse, error) {
+	return f3a.ListWithContext(context.Background(), opts)
+}
+
+// ListWithContext is used to retrieve a page of Form3 Accounts. The request is bound to ctx
+func (f3a *Form3AccountsService) ListWithContext(ctx context.Context, opts *ListOptions) (*model.AccountListResponse, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	var response model.AccountListResponse
+
+	if err := f3a.endpoint.List(ctx, opts.query(), &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// ListAll returns an iterator that walks all the pages of Form3 Accounts, starting from the page in opts
+func (f3a *Form3AccountsService) ListAll(opts *ListOptions) *AccountIterator {
+	return f3a.ListAllWithContext(context.Background(), opts)
+}
+
+// ListAllWithContext returns an iterator that walks all the pages of Form3 Accounts, starting from the page in opts.
+// Every page request is bound to ctx
+func (f3a *Form3AccountsService) ListAllWithContext(ctx context.Context, opts *ListOptions) *AccountIterator {
+	return &AccountIterator{
+		items: f3a.endpoint.Iterate(ctx, opts.query()),
+		err:   opts.validate(),
+	}
 }
@@ -1,13 +1,18 @@
 package accounts_test
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
 	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
 	"github.com/ioannisGiak89/accounts-api-client/testUtils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"net/http"
 	"net/url"
 	"testing"
 )
@@ -15,21 +20,42 @@ import (
 // Implements Form3ResourcesClient interface. This struct is used to mock the Form3RestClient
 type mockedHttpClient struct {
 	BaseUrl    *url.URL
-	MockGet    func(path string) ([]byte, error)
-	MockDelete func(path string) error
-	MockPost   func(path string, body []byte) ([]byte, error)
+	MockGet    func(ctx context.Context, path string) ([]byte, error)
+	MockDelete func(ctx context.Context, path string) error
+	MockPost   func(ctx context.Context, path string, body []byte) ([]byte, error)
+	MockPatch  func(ctx context.Context, path string, body []byte) ([]byte, error)
+}
+
+func (cl *mockedHttpClient) Patch(path string, body []byte) ([]byte, error) {
+	return cl.PatchWithContext(context.Background(), path, body)
+}
+
+func (cl *mockedHttpClient) PatchWithContext(ctx context.Context, path string, body []byte) ([]byte, error) {
+	return cl.MockPatch(ctx, path, body)
 }
 
 func (cl *mockedHttpClient) Post(path string, body []byte) ([]byte, error) {
-	return cl.MockPost(path, body)
+	return cl.PostWithContext(context.Background(), path, body)
 }
 
 func (cl *mockedHttpClient) Delete(path string) error {
-	return cl.MockDelete(path)
+	return cl.DeleteWithContext(context.Background(), path)
 }
 
 func (cl *mockedHttpClient) Get(path string) ([]byte, error) {
-	return cl.MockGet(path)
+	return cl.GetWithContext(context.Background(), path)
+}
+
+func (cl *mockedHttpClient) PostWithContext(ctx context.Context, path string, body []byte) ([]byte, error) {
+	return cl.MockPost(ctx, path, body)
+}
+
+func (cl *mockedHttpClient) DeleteWithContext(ctx context.Context, path string) error {
+	return cl.MockDelete(ctx, path)
+}
+
+func (cl *mockedHttpClient) GetWithContext(ctx context.Context, path string) ([]byte, error) {
+	return cl.MockGet(ctx, path)
 }
 
 func TestForm3AccountsService_Fetch(t *testing.T) {
@@ -45,7 +71,7 @@ func TestForm3AccountsService_Fetch(t *testing.T) {
 
 		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
 			BaseUrl: baseURL,
-			MockGet: func(path string) ([]byte, error) {
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
 				return jsonResponse, nil
 			},
 		}, "path/to/accounts/endpoint")
@@ -59,7 +85,7 @@ func TestForm3AccountsService_Fetch(t *testing.T) {
 	t.Run("should return an error if the client fails", func(t *testing.T) {
 		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
 			BaseUrl: baseURL,
-			MockGet: func(path string) ([]byte, error) {
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
 				return nil, errors.New("there was an HTTP error")
 			},
 		}, "path/to/accounts/endpoint")
@@ -70,10 +96,27 @@ func TestForm3AccountsService_Fetch(t *testing.T) {
 		assert.Equal(t, errors.New("there was an HTTP error"), err)
 	})
 
+	t.Run("should return the typed API error of the client", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return nil, &client.APIError{StatusCode: http.StatusNotFound, ErrorMessage: "record does not exist"}
+			},
+		}, "path/to/accounts/endpoint")
+
+		response, err := accountsService.Fetch(accountID)
+
+		var apiErr *client.APIError
+		assert.Nil(t, response)
+		assert.True(t, client.IsNotFound(err))
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, "record does not exist", apiErr.ErrorMessage)
+	})
+
 	t.Run("should return an error if the unmarshal fails", func(t *testing.T) {
 		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
 			BaseUrl: baseURL,
-			MockGet: func(path string) ([]byte, error) {
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
 				return []byte{12, 12}, nil
 			},
 		}, "path/to/accounts/endpoint")
@@ -94,7 +137,7 @@ func TestForm3AccountsService_Delete(t *testing.T) {
 	t.Run("should to a delete", func(t *testing.T) {
 		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
 			BaseUrl: baseURL,
-			MockDelete: func(path string) error {
+			MockDelete: func(ctx context.Context, path string) error {
 				return nil
 			},
 		}, "path/to/accounts/endpoint")
@@ -107,7 +150,7 @@ func TestForm3AccountsService_Delete(t *testing.T) {
 	t.Run("should return an error if the client fails", func(t *testing.T) {
 		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
 			BaseUrl: baseURL,
-			MockDelete: func(path string) error {
+			MockDelete: func(ctx context.Context, path string) error {
 				return errors.New("there was an HTTP error")
 			},
 		}, "path/to/accounts/endpoint")
@@ -116,6 +159,119 @@ func TestForm3AccountsService_Delete(t *testing.T) {
 
 		assert.Equal(t, errors.New("there was an HTTP error"), err)
 	})
+
+	t.Run("should send the version in decimal", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockDelete: func(ctx context.Context, path string) error {
+				assert.Equal(t, "path/to/accounts/endpoint/"+accountID.String()+"?version=12", path)
+				return nil
+			},
+		}, "path/to/accounts/endpoint/")
+
+		err := accountsService.Delete(accountID, 12)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("should not call the API if the version is negative", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{BaseUrl: baseURL}, "path/to/accounts/endpoint/")
+
+		err := accountsService.Delete(accountID, -1)
+
+		assert.True(t, errors.Is(err, accounts.ErrInvalidVersion))
+	})
+
+	t.Run("should treat a missing account as deleted when enabled", func(t *testing.T) {
+		notFound := &client.APIError{StatusCode: http.StatusNotFound}
+		mockedClient := &mockedHttpClient{
+			BaseUrl: baseURL,
+			MockDelete: func(ctx context.Context, path string) error {
+				return notFound
+			},
+		}
+
+		err := accounts.NewForm3AccountsService(mockedClient, "path/to/accounts/endpoint/").Delete(accountID, 0)
+		assert.True(t, client.IsNotFound(err))
+
+		err = accounts.NewForm3AccountsService(mockedClient, "path/to/accounts/endpoint/", accounts.WithNotFoundAsDeleted()).Delete(accountID, 0)
+		assert.Nil(t, err)
+	})
+}
+
+func TestForm3AccountsService_DeleteLatest(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+	accountID := uuid.New()
+	conflict := &client.APIError{StatusCode: http.StatusConflict, ErrorMessage: "invalid version"}
+
+	// Creates a service whose account is at version 3 and whose first deletes fail with a conflict
+	newService := func(conflicts int, deletes *[]string, opts ...accounts.ServiceOption) *accounts.Form3AccountsService {
+		return accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				response := testUtils.GetAccountApiResponse(accountID)
+				response.Data.Version = model.Version(3 + len(*deletes))
+				return json.Marshal(response)
+			},
+			MockDelete: func(ctx context.Context, path string) error {
+				*deletes = append(*deletes, path)
+
+				if len(*deletes) <= conflicts {
+					return conflict
+				}
+
+				return nil
+			},
+		}, "path/to/accounts/endpoint/", opts...)
+	}
+
+	t.Run("should delete the current version", func(t *testing.T) {
+		var deletes []string
+
+		err := newService(0, &deletes).DeleteLatest(accountID, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"path/to/accounts/endpoint/" + accountID.String() + "?version=3"}, deletes)
+	})
+
+	t.Run("should re-fetch the version on conflict", func(t *testing.T) {
+		var deletes []string
+
+		err := newService(1, &deletes).DeleteLatest(accountID, 3)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{
+			"path/to/accounts/endpoint/" + accountID.String() + "?version=3",
+			"path/to/accounts/endpoint/" + accountID.String() + "?version=4",
+		}, deletes)
+	})
+
+	t.Run("should return the conflict when attempts are exhausted", func(t *testing.T) {
+		var deletes []string
+
+		err := newService(5, &deletes).DeleteLatest(accountID, 2)
+
+		assert.True(t, client.IsConflict(err))
+		assert.Len(t, deletes, 2)
+	})
+
+	t.Run("should treat a missing account as deleted when enabled", func(t *testing.T) {
+		notFound := &client.APIError{StatusCode: http.StatusNotFound}
+		mockedClient := &mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return nil, notFound
+			},
+		}
+
+		err := accounts.NewForm3AccountsService(mockedClient, "path/to/accounts/endpoint/").DeleteLatest(accountID, 1)
+		assert.True(t, client.IsNotFound(err))
+
+		err = accounts.NewForm3AccountsService(mockedClient, "path/to/accounts/endpoint/", accounts.WithNotFoundAsDeleted()).DeleteLatest(accountID, 1)
+		assert.Nil(t, err)
+	})
 }
 
 func TestForm3AccountsService_Create(t *testing.T) {
@@ -132,7 +288,7 @@ func TestForm3AccountsService_Create(t *testing.T) {
 
 		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
 			BaseUrl: baseURL,
-			MockPost: func(path string, body []byte) ([]byte, error) {
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
 				return jsonResponse, nil
 			},
 		}, "path/to/accounts/endpoint")
@@ -145,10 +301,44 @@ func TestForm3AccountsService_Create(t *testing.T) {
 		assert.Equal(t, accountToCreate.Data.Attributes.Country, expectedResponse.Data.Attributes.Country)
 	})
 
+	t.Run("should set the default organisation ID if the account has none", func(t *testing.T) {
+		organisationID := uuid.New()
+		accountWithoutOrganisation := testUtils.GetAccountCreateRequest(accountID)
+		accountWithoutOrganisation.Data.OrganisationID = uuid.Nil
+
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				var sent model.AccountCreateRequest
+				require.NoError(t, json.Unmarshal(body, &sent))
+				assert.Equal(t, organisationID, sent.Data.OrganisationID)
+
+				return json.Marshal(model.AccountApiResponse{Data: sent.Data})
+			},
+		}, "path/to/accounts/endpoint", accounts.WithDefaultOrganisationID(organisationID))
+
+		_, err := accountsService.Create(accountWithoutOrganisation)
+
+		assert.Nil(t, err)
+		assert.Equal(t, uuid.Nil, accountWithoutOrganisation.Data.OrganisationID)
+	})
+
+	t.Run("should not call the API if the validation fails", func(t *testing.T) {
+		invalidAccount := testUtils.GetAccountCreateRequest(accountID)
+		invalidAccount.Data.Attributes.Country = ""
+
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{BaseUrl: baseURL}, "path/to/accounts/endpoint", accounts.WithValidation())
+
+		response, err := accountsService.Create(invalidAccount)
+
+		assert.Nil(t, response)
+		assert.True(t, errors.Is(err, accounts.ErrInvalidAccount))
+	})
+
 	t.Run("should return an error if the client fails", func(t *testing.T) {
 		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
 			BaseUrl: baseURL,
-			MockPost: func(path string, body []byte) ([]byte, error) {
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
 				return nil, errors.New("there was an HTTP error")
 			},
 		}, "path/to/accounts/endpoint")
@@ -162,7 +352,7 @@ func TestForm3AccountsService_Create(t *testing.T) {
 	t.Run("should return an error if the unmarshal fails", func(t *testing.T) {
 		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
 			BaseUrl: baseURL,
-			MockPost: func(path string, body []byte) ([]byte, error) {
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
 				return []byte{12, 12}, nil
 			},
 		}, "path/to/accounts/endpoint")
@@ -173,3 +363,541 @@ func TestForm3AccountsService_Create(t *testing.T) {
 		assert.Nil(t, response)
 	})
 }
+
+func TestForm3AccountsService_CreateDuplicate(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+	accountID := uuid.New()
+	conflict := &client.APIError{StatusCode: http.StatusConflict, ErrorMessage: "Account cannot be created as it violates a duplicate constraint"}
+
+	// Creates a service whose account already exists with the given attributes
+	newService := func(existing *model.AccountApiResponse) *accounts.Form3AccountsService {
+		return accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return nil, conflict
+			},
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				assert.Equal(t, "path/to/accounts/endpoint/"+accountID.String(), path)
+				return json.Marshal(existing)
+			},
+		}, "path/to/accounts/endpoint/")
+	}
+
+	t.Run("should send the idempotency key of the context", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				assert.Equal(t, "my-key", client.IdempotencyKeyFromContext(ctx))
+				return json.Marshal(testUtils.GetAccountApiResponse(accountID))
+			},
+		}, "path/to/accounts/endpoint/")
+
+		_, err := accountsService.CreateWithContext(client.ContextWithIdempotencyKey(context.Background(), "my-key"), testUtils.GetAccountCreateRequest(accountID))
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("should return the existing account if its attributes are the same", func(t *testing.T) {
+		existing := testUtils.GetAccountApiResponse(accountID)
+		existing.Data.Attributes.Status = "confirmed"
+
+		response, err := newService(existing).Create(testUtils.GetAccountCreateRequest(accountID))
+
+		assert.Nil(t, err)
+		assert.Equal(t, existing, response)
+	})
+
+	t.Run("should return the conflict if the attributes are different", func(t *testing.T) {
+		existing := testUtils.GetAccountApiResponse(accountID)
+		existing.Data.Attributes.Name = []string{"Someone Else"}
+
+		response, err := newService(existing).Create(testUtils.GetAccountCreateRequest(accountID))
+
+		assert.Nil(t, response)
+		assert.True(t, client.IsConflict(err))
+	})
+
+	t.Run("should return the existing account whatever its attributes on create or get", func(t *testing.T) {
+		existing := testUtils.GetAccountApiResponse(accountID)
+		existing.Data.Attributes.Name = []string{"Someone Else"}
+
+		response, created, err := newService(existing).CreateOrGet(testUtils.GetAccountCreateRequest(accountID))
+
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Equal(t, existing, response)
+	})
+
+	t.Run("should report a created account on create or get", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return json.Marshal(testUtils.GetAccountApiResponse(accountID))
+			},
+		}, "path/to/accounts/endpoint/")
+
+		response, created, err := accountsService.CreateOrGet(testUtils.GetAccountCreateRequest(accountID))
+
+		assert.Nil(t, err)
+		assert.True(t, created)
+		assert.Equal(t, accountID, response.Data.ID)
+	})
+
+	t.Run("should require an account ID on create or get", func(t *testing.T) {
+		_, _, err := newService(nil).CreateOrGet(testUtils.GetAccountCreateRequest(uuid.Nil))
+
+		assert.True(t, errors.Is(err, accounts.ErrMissingAccountID))
+	})
+}
+
+func TestForm3AccountsService_WithContext(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+	accountID := uuid.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("should pass the context to the client on fetch", func(t *testing.T) {
+		jsonResponse, err := json.Marshal(testUtils.GetAccountApiResponse(accountID))
+		require.NoError(t, err)
+
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(receivedCtx context.Context, path string) ([]byte, error) {
+				assert.Equal(t, ctx, receivedCtx)
+				return jsonResponse, nil
+			},
+		}, "path/to/accounts/endpoint")
+
+		_, err = accountsService.FetchWithContext(ctx, accountID)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("should pass the context to the client on create", func(t *testing.T) {
+		jsonResponse, err := json.Marshal(testUtils.GetAccountApiResponse(accountID))
+		require.NoError(t, err)
+
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockPost: func(receivedCtx context.Context, path string, body []byte) ([]byte, error) {
+				assert.Equal(t, ctx, receivedCtx)
+				return jsonResponse, nil
+			},
+		}, "path/to/accounts/endpoint")
+
+		_, err = accountsService.CreateWithContext(ctx, testUtils.GetAccountCreateRequest(accountID))
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("should pass the context to the client on delete", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockDelete: func(receivedCtx context.Context, path string) error {
+				assert.Equal(t, ctx, receivedCtx)
+				return nil
+			},
+		}, "path/to/accounts/endpoint")
+
+		err := accountsService.DeleteWithContext(ctx, accountID, 0)
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestForm3AccountsService_List(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+
+	t.Run("should request a page and return an AccountListResponse", func(t *testing.T) {
+		expectedResponse := &model.AccountListResponse{
+			Data: []model.Account{
+				testUtils.GetAccountApiResponse(uuid.New()).Data,
+				testUtils.GetAccountApiResponse(uuid.New()).Data,
+			},
+			Links: model.Links{
+				Self:  "/v1/organisation/accounts?page%5Bnumber%5D=1&page%5Bsize%5D=2",
+				First: "/v1/organisation/accounts?page%5Bnumber%5D=first&page%5Bsize%5D=2",
+				Next:  "/v1/organisation/accounts?page%5Bnumber%5D=2&page%5Bsize%5D=2",
+				Prev:  "/v1/organisation/accounts?page%5Bnumber%5D=0&page%5Bsize%5D=2",
+				Last:  "/v1/organisation/accounts?page%5Bnumber%5D=last&page%5Bsize%5D=2",
+			},
+		}
+		jsonResponse, err := json.Marshal(expectedResponse)
+		require.NoError(t, err)
+
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				assert.Equal(t, "path/to/accounts/endpoint?page%5Bnumber%5D=1&page%5Bsize%5D=2", path)
+				return jsonResponse, nil
+			},
+		}, "path/to/accounts/endpoint/")
+
+		response, err := accountsService.List(&accounts.ListOptions{PageNumber: 1, PageSize: 2})
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResponse, response)
+	})
+
+	t.Run("should not add query parameters without options", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				assert.Equal(t, "path/to/accounts/endpoint", path)
+				return []byte(`{"data":[]}`), nil
+			},
+		}, "path/to/accounts/endpoint/")
+
+		response, err := accountsService.List(nil)
+
+		assert.Nil(t, err)
+		assert.Empty(t, response.Data)
+	})
+
+	t.Run("should encode the filter", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				u, err := url.Parse(path)
+				require.NoError(t, err)
+				assert.Equal(t, url.Values{
+					"page[size]":             {"10"},
+					"filter[bank_id]":        {"400300"},
+					"filter[bank_id_code]":   {"GBDSC"},
+					"filter[account_number]": {"41426819"},
+					"filter[iban]":           {"GB16NWBK40030041426819"},
+					"filter[customer_id]":    {"customer-1"},
+					"filter[country]":        {"GB"},
+				}, u.Query())
+				return []byte(`{"data":[]}`), nil
+			},
+		}, "path/to/accounts/endpoint")
+
+		_, err := accountsService.List(&accounts.ListOptions{
+			PageSize: 10,
+			Filter: &accounts.Filter{
+				BankID:        "400300",
+				BankIDCode:    "GBDSC",
+				AccountNumber: "41426819",
+				IBAN:          "GB16NWBK40030041426819",
+				CustomerID:    "customer-1",
+				Country:       "GB",
+			},
+		})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("should not call the API if the filter is invalid", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				t.Fatal("the API should not be called")
+				return nil, nil
+			},
+		}, "path/to/accounts/endpoint")
+
+		response, err := accountsService.List(&accounts.ListOptions{Filter: &accounts.Filter{Country: "gb"}})
+
+		assert.Nil(t, response)
+		assert.True(t, errors.Is(err, accounts.ErrInvalidFilter))
+	})
+
+	t.Run("should return an error if the client fails", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return nil, errors.New("there was an HTTP error")
+			},
+		}, "path/to/accounts/endpoint")
+
+		response, err := accountsService.List(nil)
+
+		assert.Nil(t, response)
+		assert.Equal(t, errors.New("there was an HTTP error"), err)
+	})
+}
+
+func TestForm3AccountsService_ListAll(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+
+	// Returns 3 pages of 2, 2 and 1 accounts
+	pages := [][]model.Account{
+		{testUtils.GetAccountApiResponse(uuid.New()).Data, testUtils.GetAccountApiResponse(uuid.New()).Data},
+		{testUtils.GetAccountApiResponse(uuid.New()).Data, testUtils.GetAccountApiResponse(uuid.New()).Data},
+		{testUtils.GetAccountApiResponse(uuid.New()).Data},
+	}
+	pagedGet := func(requestedPages *[]string) func(ctx context.Context, path string) ([]byte, error) {
+		return func(ctx context.Context, path string) ([]byte, error) {
+			u, err := url.Parse(path)
+			require.NoError(t, err)
+			pageNumber := u.Query().Get("page[number]")
+			*requestedPages = append(*requestedPages, pageNumber)
+
+			index := len(*requestedPages) - 1
+			response := model.AccountListResponse{Data: pages[index]}
+
+			if index < len(pages)-1 {
+				response.Links.Next = "next"
+			}
+
+			return json.Marshal(response)
+		}
+	}
+
+	t.Run("should walk all the pages lazily", func(t *testing.T) {
+		var requestedPages []string
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: pagedGet(&requestedPages),
+		}, "path/to/accounts/endpoint")
+
+		it := accountsService.ListAll(&accounts.ListOptions{PageSize: 2})
+		assert.Empty(t, requestedPages)
+
+		var ids []uuid.UUID
+		for it.Next() {
+			ids = append(ids, it.Account().ID)
+		}
+
+		assert.Nil(t, it.Err())
+		assert.Equal(t, []string{"", "1", "2"}, requestedPages)
+		assert.Equal(t, []uuid.UUID{pages[0][0].ID, pages[0][1].ID, pages[1][0].ID, pages[1][1].ID, pages[2][0].ID}, ids)
+		assert.False(t, it.Next())
+	})
+
+	t.Run("should stop and return the error if a page fails", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return nil, errors.New("there was an HTTP error")
+			},
+		}, "path/to/accounts/endpoint")
+
+		it := accountsService.ListAll(nil)
+
+		assert.False(t, it.Next())
+		assert.Equal(t, errors.New("there was an HTTP error"), it.Err())
+	})
+}
+
+func TestForm3AccountsService_Update(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+	accountID := uuid.New()
+
+	t.Run("should patch the account and return the new version", func(t *testing.T) {
+		expectedResponse := testUtils.GetAccountApiResponse(accountID)
+		expectedResponse.Data.Version = 1
+		expectedResponse.Data.Attributes.Name = []string{"Samantha Holder-Smith"}
+		jsonResponse, err := json.Marshal(expectedResponse)
+		require.NoError(t, err)
+
+		accountToUpdate := &model.AccountUpdateRequest{Data: testUtils.GetAccountApiResponse(accountID).Data}
+		accountToUpdate.Data.Attributes.Name = []string{"Samantha Holder-Smith"}
+
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockPatch: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				assert.Equal(t, "path/to/accounts/endpoint/"+accountID.String(), path)
+
+				var sent model.AccountUpdateRequest
+				require.NoError(t, json.Unmarshal(body, &sent))
+				assert.Equal(t, model.Version(0), sent.Data.Version)
+				assert.Equal(t, []string{"Samantha Holder-Smith"}, sent.Data.Attributes.Name)
+
+				return jsonResponse, nil
+			},
+		}, "path/to/accounts/endpoint/")
+
+		response, err := accountsService.Update(accountToUpdate)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedResponse, response)
+		assert.Equal(t, model.Version(1), response.Data.Version)
+	})
+
+	t.Run("should not call the API if the version is negative", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{BaseUrl: baseURL}, "path/to/accounts/endpoint/")
+		accountToUpdate := &model.AccountUpdateRequest{Data: testUtils.GetAccountApiResponse(accountID).Data}
+		accountToUpdate.Data.Version = -1
+
+		response, err := accountsService.Update(accountToUpdate)
+
+		assert.Nil(t, response)
+		assert.True(t, errors.Is(err, accounts.ErrInvalidVersion))
+	})
+
+	t.Run("should return a conflict error if the version is stale", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockPatch: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return nil, &client.APIError{StatusCode: http.StatusConflict, ErrorMessage: "invalid version"}
+			},
+		}, "path/to/accounts/endpoint/")
+
+		response, err := accountsService.Update(&model.AccountUpdateRequest{Data: testUtils.GetAccountApiResponse(accountID).Data})
+
+		assert.Nil(t, response)
+		assert.True(t, client.IsConflict(err))
+	})
+
+	t.Run("should return an error if the unmarshal fails", func(t *testing.T) {
+		accountsService := accounts.NewForm3AccountsService(&mockedHttpClient{
+			BaseUrl: baseURL,
+			MockPatch: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return []byte{12, 12}, nil
+			},
+		}, "path/to/accounts/endpoint/")
+
+		response, err := accountsService.Update(&model.AccountUpdateRequest{Data: testUtils.GetAccountApiResponse(accountID).Data})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, response)
+	})
+}
+
+func TestForm3AccountsService_Mutate(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+	accountID := uuid.New()
+
+	// Returns a client whose account version is bumped by someone else on every fetch, until conflicts run out
+	newConflictingClient := func(conflicts int, fetches *int, patches *int) *mockedHttpClient {
+		return &mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				*fetches++
+				response := testUtils.GetAccountApiResponse(accountID)
+				response.Data.Version = model.Version(*fetches)
+				return json.Marshal(response)
+			},
+			MockPatch: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				*patches++
+
+				if *patches <= conflicts {
+					return nil, &client.APIError{StatusCode: http.StatusConflict, ErrorMessage: "invalid version"}
+				}
+
+				var sent model.AccountUpdateRequest
+				require.NoError(t, json.Unmarshal(body, &sent))
+				sent.Data.Version++
+
+				return json.Marshal(model.AccountApiResponse{Data: sent.Data})
+			},
+		}
+	}
+
+	t.Run("should re-fetch and re-apply the mutation on conflict", func(t *testing.T) {
+		fetches, patches := 0, 0
+		accountsService := accounts.NewForm3AccountsService(newConflictingClient(2, &fetches, &patches), "path/to/accounts/endpoint/")
+
+		response, err := accountsService.Mutate(accountID, 3, func(account *model.Account) error {
+			account.Attributes.Name = []string{"Samantha Holder-Smith"}
+			return nil
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, fetches)
+		assert.Equal(t, 3, patches)
+		assert.Equal(t, model.Version(4), response.Data.Version)
+		assert.Equal(t, []string{"Samantha Holder-Smith"}, response.Data.Attributes.Name)
+	})
+
+	t.Run("should return the conflict when attempts are exhausted", func(t *testing.T) {
+		fetches, patches := 0, 0
+		accountsService := accounts.NewForm3AccountsService(newConflictingClient(5, &fetches, &patches), "path/to/accounts/endpoint/")
+
+		response, err := accountsService.Mutate(accountID, 2, func(account *model.Account) error {
+			return nil
+		})
+
+		assert.Nil(t, response)
+		assert.True(t, client.IsConflict(err))
+		assert.Equal(t, 2, patches)
+	})
+
+	t.Run("should abort if the mutation fails", func(t *testing.T) {
+		fetches, patches := 0, 0
+		accountsService := accounts.NewForm3AccountsService(newConflictingClient(0, &fetches, &patches), "path/to/accounts/endpoint/")
+
+		response, err := accountsService.Mutate(accountID, 3, func(account *model.Account) error {
+			return errors.New("mutation failed")
+		})
+
+		assert.Nil(t, response)
+		assert.Equal(t, errors.New("mutation failed"), err)
+		assert.Equal(t, 0, patches)
+	})
+}
+
+func TestForm3AccountsService_WithTracer(t *testing.T) {
+
+	baseURL, err := url.Parse("http://localhost:8080/")
+	require.NoError(t, err)
+	accountID := uuid.New()
+
+	t.Run("should record a span for every operation", func(t *testing.T) {
+		exporter := tracing.NewInMemoryExporter()
+		var requestSpan tracing.SpanContext
+
+		httpClient := &mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				requestSpan = tracing.SpanContextFromContext(ctx)
+				return nil, &client.APIError{StatusCode: http.StatusNotFound}
+			},
+		}
+
+		accountsService := accounts.NewForm3AccountsService(httpClient, "path/to/accounts/endpoint/", accounts.WithTracer(tracing.NewTracer(exporter)))
+
+		_, err := accountsService.Fetch(accountID)
+		require.True(t, client.IsNotFound(err))
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "accounts.fetch", spans[0].Name)
+		assert.Equal(t, "accounts.fetch", spans[0].Attributes[tracing.AttributeOperation])
+		assert.Equal(t, accountID.String(), spans[0].Attributes[tracing.AttributeAccountID])
+		assert.Equal(t, err, spans[0].Err)
+		assert.Equal(t, spans[0].SpanContext, requestSpan)
+	})
+
+	t.Run("should nest the spans of a mutation", func(t *testing.T) {
+		exporter := tracing.NewInMemoryExporter()
+
+		httpClient := &mockedHttpClient{
+			BaseUrl: baseURL,
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return json.Marshal(testUtils.GetAccountApiResponse(accountID))
+			},
+			MockPatch: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return json.Marshal(testUtils.GetAccountApiResponse(accountID))
+			},
+		}
+
+		accountsService := accounts.NewForm3AccountsService(httpClient, "path/to/accounts/endpoint/", accounts.WithTracer(tracing.NewTracer(exporter)))
+
+		_, err := accountsService.Mutate(accountID, 3, func(account *model.Account) error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 3)
+		assert.Equal(t, []string{"accounts.fetch", "accounts.update", "accounts.mutate"}, []string{spans[0].Name, spans[1].Name, spans[2].Name})
+		assert.Equal(t, spans[2].SpanContext.SpanID, spans[0].ParentSpanID)
+		assert.Equal(t, spans[2].SpanContext.SpanID, spans[1].ParentSpanID)
+		assert.Equal(t, 1, spans[2].Attributes[tracing.AttributeAttempts])
+	})
+}
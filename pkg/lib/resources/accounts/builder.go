@@ -0,0 +1,193 @@
+package accounts
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+)
+
+// AccountBuilder builds an AccountCreateRequest. The account has the accounts type and a new random ID unless
+// WithID sets one. Build validates the account, so an invalid one never reaches the API
+//
+//	request, err := accounts.NewGBAccount("400300", "41426819").
+//		WithBIC("NWBKGB22").
+//		WithName("Samantha Holder").
+//		WithGeneratedIBAN().
+//		Build()
+type AccountBuilder struct {
+	account      model.Account
+	generateIBAN bool
+}
+
+// NewAccountBuilder creates an AccountBuilder for an account of the given country
+func NewAccountBuilder(country string) *AccountBuilder {
+	return &AccountBuilder{
+		account: model.Account{
+			ID:         uuid.New(),
+			Type:       "accounts",
+			Attributes: model.AccountAttributes{Country: country},
+		},
+	}
+}
+
+// newCountryAccount creates an AccountBuilder with the bank details and the currency of a country
+func newCountryAccount(country string, bankIDCode string, currency string, bankID string, accountNumber string) *AccountBuilder {
+	return NewAccountBuilder(country).
+		WithBankID(bankID, bankIDCode).
+		WithAccountNumber(accountNumber).
+		WithBaseCurrency(currency)
+}
+
+// NewGBAccount creates an AccountBuilder for a GB account with a sort code. GB accounts need a BIC too
+func NewGBAccount(sortCode string, accountNumber string) *AccountBuilder {
+	return newCountryAccount("GB", "GBDSC", "GBP", sortCode, accountNumber)
+}
+
+// NewDEAccount creates an AccountBuilder for a DE account with a Bankleitzahl
+func NewDEAccount(blz string, accountNumber string) *AccountBuilder {
+	return newCountryAccount("DE", "DEBLZ", "EUR", blz, accountNumber)
+}
+
+// NewFRAccount creates an AccountBuilder for a FR account with the bank and branch codes as bank ID
+func NewFRAccount(bankID string, accountNumber string) *AccountBuilder {
+	return newCountryAccount("FR", "FR", "EUR", bankID, accountNumber)
+}
+
+// NewESAccount creates an AccountBuilder for an ES account with the bank and branch codes as bank ID
+func NewESAccount(bankID string, accountNumber string) *AccountBuilder {
+	return newCountryAccount("ES", "ESNCC", "EUR", bankID, accountNumber)
+}
+
+// NewITAccount creates an AccountBuilder for an IT account with the ABI and CAB codes as bank ID
+func NewITAccount(bankID string, accountNumber string) *AccountBuilder {
+	return newCountryAccount("IT", "ITNCC", "EUR", bankID, accountNumber)
+}
+
+// NewNLAccount creates an AccountBuilder for a NL account. NL accounts need a BIC too
+func NewNLAccount(accountNumber string) *AccountBuilder {
+	return NewAccountBuilder("NL").WithAccountNumber(accountNumber).WithBaseCurrency("EUR")
+}
+
+// NewAUAccount creates an AccountBuilder for an AU account with a BSB code. AU accounts need a BIC too
+func NewAUAccount(bsb string, accountNumber string) *AccountBuilder {
+	return newCountryAccount("AU", "AUBSB", "AUD", bsb, accountNumber)
+}
+
+// NewCAAccount creates an AccountBuilder for a CA account with a routing number. CA accounts need a BIC too
+func NewCAAccount(routingNumber string, accountNumber string) *AccountBuilder {
+	return newCountryAccount("CA", "CACPA", "CAD", routingNumber, accountNumber)
+}
+
+// NewUSAccount creates an AccountBuilder for a US account with an ABA routing number. US accounts need a BIC too
+func NewUSAccount(routingNumber string, accountNumber string) *AccountBuilder {
+	return newCountryAccount("US", "USABA", "USD", routingNumber, accountNumber)
+}
+
+// WithID sets the ID of the account, e.g. to create it with CreateOrGet
+func (b *AccountBuilder) WithID(id uuid.UUID) *AccountBuilder {
+	b.account.ID = id
+	return b
+}
+
+// WithOrganisationID sets the organisation of the account. When not set, the service sets its default one
+func (b *AccountBuilder) WithOrganisationID(organisationID uuid.UUID) *AccountBuilder {
+	b.account.OrganisationID = organisationID
+	return b
+}
+
+// WithName sets the names of the account holder
+func (b *AccountBuilder) WithName(names ...string) *AccountBuilder {
+	b.account.Attributes.Name = names
+	return b
+}
+
+// WithAlternativeNames sets the alternative names of the account holder
+func (b *AccountBuilder) WithAlternativeNames(names ...string) *AccountBuilder {
+	b.account.Attributes.AlternativeNames = names
+	return b
+}
+
+// WithBankID sets the bank ID and the kind of bank ID
+func (b *AccountBuilder) WithBankID(bankID string, bankIDCode string) *AccountBuilder {
+	b.account.Attributes.BankID = bankID
+	b.account.Attributes.BankIDCode = bankIDCode
+	return b
+}
+
+// WithBIC sets the BIC of the bank
+func (b *AccountBuilder) WithBIC(bic string) *AccountBuilder {
+	b.account.Attributes.Bic = bic
+	return b
+}
+
+// WithAccountNumber sets the account number
+func (b *AccountBuilder) WithAccountNumber(accountNumber string) *AccountBuilder {
+	b.account.Attributes.AccountNumber = accountNumber
+	return b
+}
+
+// WithIBAN sets the IBAN
+func (b *AccountBuilder) WithIBAN(iban string) *AccountBuilder {
+	b.account.Attributes.Iban = iban
+	b.generateIBAN = false
+	return b
+}
+
+// WithGeneratedIBAN makes Build set the IBAN built from the bank details, see SetIBAN
+func (b *AccountBuilder) WithGeneratedIBAN() *AccountBuilder {
+	b.generateIBAN = true
+	return b
+}
+
+// WithBaseCurrency sets the ISO 4217 base currency
+func (b *AccountBuilder) WithBaseCurrency(currency string) *AccountBuilder {
+	b.account.Attributes.BaseCurrency = currency
+	return b
+}
+
+// WithClassification sets the account classification, model.AccountClassificationPersonal or
+// model.AccountClassificationBusiness
+func (b *AccountBuilder) WithClassification(classification string) *AccountBuilder {
+	b.account.Attributes.AccountClassification = classification
+	return b
+}
+
+// WithJointAccount sets whether the account is held by more than one person
+func (b *AccountBuilder) WithJointAccount(joint bool) *AccountBuilder {
+	b.account.Attributes.JointAccount = model.Bool(joint)
+	return b
+}
+
+// WithAccountMatchingOptOut sets whether the account opted out of account matching
+func (b *AccountBuilder) WithAccountMatchingOptOut(optOut bool) *AccountBuilder {
+	b.account.Attributes.AccountMatchingOptOut = model.Bool(optOut)
+	return b
+}
+
+// WithSecondaryIdentification sets the secondary identification, e.g. a building society roll number
+func (b *AccountBuilder) WithSecondaryIdentification(identification string) *AccountBuilder {
+	b.account.Attributes.SecondaryIdentification = identification
+	return b
+}
+
+// Build validates the account and returns the request that creates it. The errors are reported like
+// ValidateCreateRequest does, except that the organisation ID isn't required
+func (b *AccountBuilder) Build() (*model.AccountCreateRequest, error) {
+	account := b.account
+	account.Attributes.Name = append([]string(nil), b.account.Attributes.Name...)
+	account.Attributes.AlternativeNames = append([]string(nil), b.account.Attributes.AlternativeNames...)
+
+	var errs []FieldError
+
+	if b.generateIBAN {
+		if err := SetIBAN(&account.Attributes); err != nil {
+			errs = append(errs, FieldError{Field: "iban", Message: fmt.Sprintf("can't be generated: %v", err)})
+		}
+	}
+
+	if errs = append(errs, validateAccount(account, false)...); len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+
+	return &model.AccountCreateRequest{Data: account}, nil
+}
@@ -0,0 +1,122 @@
+package accounts_test
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAccountBuilder_Build(t *testing.T) {
+
+	t.Run("should build a GB account with the defaults", func(t *testing.T) {
+		request, err := accounts.NewGBAccount("400300", "41426819").
+			WithBIC("NWBKGB22").
+			WithName("Samantha Holder").
+			WithGeneratedIBAN().
+			Build()
+		require.NoError(t, err)
+
+		assert.NotEqual(t, uuid.Nil, request.Data.ID)
+		assert.Equal(t, "accounts", request.Data.Type)
+		assert.Equal(t, model.AccountAttributes{
+			AccountNumber: "41426819",
+			BankID:        "400300",
+			BankIDCode:    "GBDSC",
+			BaseCurrency:  "GBP",
+			Bic:           "NWBKGB22",
+			Country:       "GB",
+			Iban:          "GB16NWBK40030041426819",
+			Name:          []string{"Samantha Holder"},
+		}, request.Data.Attributes)
+	})
+
+	t.Run("should build the accounts of other countries", func(t *testing.T) {
+		tests := []struct {
+			country  string
+			builder  *accounts.AccountBuilder
+			currency string
+			iban     string
+		}{
+			{"DE", accounts.NewDEAccount("37040044", "0532013000").WithGeneratedIBAN(), "EUR", "DE89370400440532013000"},
+			{"FR", accounts.NewFRAccount("2004101005", "0500013M026").WithGeneratedIBAN(), "EUR", "FR1420041010050500013M02606"},
+			{"ES", accounts.NewESAccount("21000418", "0200051332").WithGeneratedIBAN(), "EUR", "ES9121000418450200051332"},
+			{"IT", accounts.NewITAccount("0542811101", "000000123456").WithGeneratedIBAN(), "EUR", "IT60X0542811101000000123456"},
+			{"NL", accounts.NewNLAccount("0417164300").WithBIC("ABNANL2A").WithGeneratedIBAN(), "EUR", "NL91ABNA0417164300"},
+			{"AU", accounts.NewAUAccount("033000", "12345678").WithBIC("WPACAU2S"), "AUD", ""},
+			{"CA", accounts.NewCAAccount("012345678", "1234567").WithBIC("ROYCCAT2"), "CAD", ""},
+			{"US", accounts.NewUSAccount("021000021", "123456789").WithBIC("CHASUS33"), "USD", ""},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.country, func(t *testing.T) {
+				request, err := tt.builder.WithName("Samantha Holder").Build()
+				require.NoError(t, err)
+
+				assert.Equal(t, tt.country, request.Data.Attributes.Country)
+				assert.Equal(t, tt.currency, request.Data.Attributes.BaseCurrency)
+				assert.Equal(t, tt.iban, request.Data.Attributes.Iban)
+			})
+		}
+	})
+
+	t.Run("should pad a short account number when the IBAN is generated", func(t *testing.T) {
+		request, err := accounts.NewDEAccount("37040044", "532013000").
+			WithName("Samantha Holder").
+			WithGeneratedIBAN().
+			Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, "DE89370400440532013000", request.Data.Attributes.Iban)
+		assert.Equal(t, "0532013000", request.Data.Attributes.AccountNumber)
+	})
+
+	t.Run("should set the optional attributes", func(t *testing.T) {
+		accountID := uuid.New()
+		organisationID := uuid.New()
+
+		request, err := accounts.NewDEAccount("37040044", "0532013000").
+			WithID(accountID).
+			WithOrganisationID(organisationID).
+			WithName("Samantha Holder").
+			WithAlternativeNames("Sam Holder").
+			WithClassification(model.AccountClassificationBusiness).
+			WithJointAccount(true).
+			WithAccountMatchingOptOut(false).
+			WithSecondaryIdentification("A1B2C3D4").
+			WithIBAN("DE89370400440532013000").
+			Build()
+		require.NoError(t, err)
+
+		assert.Equal(t, accountID, request.Data.ID)
+		assert.Equal(t, organisationID, request.Data.OrganisationID)
+		assert.Equal(t, []string{"Sam Holder"}, request.Data.Attributes.AlternativeNames)
+		assert.Equal(t, model.AccountClassificationBusiness, request.Data.Attributes.AccountClassification)
+		assert.Equal(t, model.Bool(true), request.Data.Attributes.JointAccount)
+		assert.Equal(t, model.Bool(false), request.Data.Attributes.AccountMatchingOptOut)
+		assert.Equal(t, "A1B2C3D4", request.Data.Attributes.SecondaryIdentification)
+		assert.Equal(t, "DE89370400440532013000", request.Data.Attributes.Iban)
+	})
+
+	t.Run("should report all the problems of an invalid account", func(t *testing.T) {
+		_, err := accounts.NewGBAccount("40030", "41426819").WithGeneratedIBAN().Build()
+
+		assert.True(t, errors.Is(err, accounts.ErrInvalidAccount))
+		assert.Equal(t, []string{"iban", "name", "bank_id", "bic"}, invalidFields(t, err))
+	})
+
+	t.Run("should not share the names with the built requests", func(t *testing.T) {
+		builder := accounts.NewDEAccount("37040044", "0532013000").WithName("Samantha Holder")
+
+		first, err := builder.Build()
+		require.NoError(t, err)
+		first.Data.Attributes.Name[0] = "Someone Else"
+
+		second, err := builder.Build()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Samantha Holder"}, second.Data.Attributes.Name)
+	})
+}
@@ -0,0 +1,46 @@
+package accounts
+
+import (
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/iban"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+)
+
+// SetIBAN sets the IBAN of an account to the one built from its country, bank ID, BIC and account number, see
+// iban.Build. The account number is set to the one in the IBAN, so a short numeric one is padded to the national
+// format like validation expects. Returns an error wrapping iban.ErrUnsupportedCountry for the countries an IBAN
+// can't be built for
+func SetIBAN(attributes *model.AccountAttributes) error {
+	built, err := iban.Build(attributes.Country, attributes.BankID, attributes.Bic, attributes.AccountNumber)
+
+	if err != nil {
+		return err
+	}
+
+	parsed, err := iban.Parse(built)
+
+	if err != nil {
+		return err
+	}
+
+	attributes.Iban = built
+	attributes.AccountNumber = parsed.AccountNumber
+
+	return nil
+}
+
+// SetBankDetailsFromIBAN validates an IBAN and sets it on an account along with the country, bank ID and account
+// number derived from it
+func SetBankDetailsFromIBAN(attributes *model.AccountAttributes, value string) error {
+	parsed, err := iban.Parse(value)
+
+	if err != nil {
+		return err
+	}
+
+	attributes.Iban = parsed.String()
+	attributes.Country = parsed.Country
+	attributes.BankID = parsed.BankID
+	attributes.AccountNumber = parsed.AccountNumber
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package accounts_test
+
+import (
+	"errors"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/iban"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSetIBAN(t *testing.T) {
+
+	t.Run("should build and set the IBAN from the bank details", func(t *testing.T) {
+		attributes := model.AccountAttributes{Country: "GB", BankID: "601613", Bic: "NWBKGB2L", AccountNumber: "31926819"}
+
+		require.NoError(t, accounts.SetIBAN(&attributes))
+
+		assert.Equal(t, "GB29NWBK60161331926819", attributes.Iban)
+	})
+
+	t.Run("should pad a short account number like the IBAN", func(t *testing.T) {
+		attributes := model.AccountAttributes{Country: "DE", BankID: "37040044", AccountNumber: "532013000"}
+
+		require.NoError(t, accounts.SetIBAN(&attributes))
+
+		assert.Equal(t, "DE89370400440532013000", attributes.Iban)
+		assert.Equal(t, "0532013000", attributes.AccountNumber)
+	})
+
+	t.Run("should not build the IBAN of an unsupported country", func(t *testing.T) {
+		attributes := model.AccountAttributes{Country: "US", BankID: "021000021", AccountNumber: "123456789"}
+
+		err := accounts.SetIBAN(&attributes)
+
+		assert.True(t, errors.Is(err, iban.ErrUnsupportedCountry))
+		assert.Empty(t, attributes.Iban)
+	})
+}
+
+func TestSetBankDetailsFromIBAN(t *testing.T) {
+
+	t.Run("should set the bank details from an IBAN", func(t *testing.T) {
+		var attributes model.AccountAttributes
+
+		require.NoError(t, accounts.SetBankDetailsFromIBAN(&attributes, "DE89 3704 0044 0532 0130 00"))
+
+		assert.Equal(t, model.AccountAttributes{
+			Country:       "DE",
+			BankID:        "37040044",
+			AccountNumber: "0532013000",
+			Iban:          "DE89370400440532013000",
+		}, attributes)
+	})
+
+	t.Run("should reject an invalid IBAN", func(t *testing.T) {
+		var attributes model.AccountAttributes
+
+		err := accounts.SetBankDetailsFromIBAN(&attributes, "DE88370400440532013000")
+
+		assert.True(t, errors.Is(err, iban.ErrInvalidIBAN))
+		assert.Equal(t, model.AccountAttributes{}, attributes)
+	})
+}
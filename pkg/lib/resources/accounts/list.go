@@ -0,0 +1,163 @@
+package accounts
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/resource"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidFilter is returned when a Filter fails the client side validation
+var ErrInvalidFilter = errors.New("accounts: invalid filter")
+
+var (
+	countryPattern    = regexp.MustCompile(`^[A-Z]{2}$`)
+	bankIDPattern     = regexp.MustCompile(`^[A-Z0-9]{1,11}$`)
+	bankIDCodePattern = regexp.MustCompile(`^[A-Z]{2,5}$`)
+	accountNumPattern = regexp.MustCompile(`^[A-Z0-9]{1,64}$`)
+	ibanPattern       = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{1,30}$`)
+)
+
+// ListOptions holds the pagination parameters and the filter of a list request. Zero values are left
+// to the API defaults
+type ListOptions struct {
+	PageNumber int
+	PageSize   int
+	Filter     *Filter
+}
+
+// Filter holds the filter[...] parameters of a list request. Only accounts matching all the non-empty
+// fields are returned
+type Filter struct {
+	BankID        string
+	BankIDCode    string
+	AccountNumber string
+	IBAN          string
+	CustomerID    string
+	Country       string
+}
+
+// Validate checks the filter values before they are sent to the API. All the invalid fields are reported
+// in a single error wrapping ErrInvalidFilter
+func (f *Filter) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	var problems []string
+
+	check := func(name string, value string, pattern *regexp.Regexp, format string) {
+		if value != "" && !pattern.MatchString(value) {
+			problems = append(problems, fmt.Sprintf("%s %q must be %s", name, value, format))
+		}
+	}
+
+	check("bank_id", f.BankID, bankIDPattern, "up to 11 uppercase alphanumeric characters")
+	check("bank_id_code", f.BankIDCode, bankIDCodePattern, "2 to 5 uppercase letters")
+	check("account_number", f.AccountNumber, accountNumPattern, "up to 64 uppercase alphanumeric characters")
+	check("iban", f.IBAN, ibanPattern, "an IBAN in electronic format")
+	check("country", f.Country, countryPattern, "an ISO 3166-1 alpha-2 code")
+
+	if len(f.CustomerID) > 256 || (f.CustomerID != "" && strings.TrimSpace(f.CustomerID) == "") {
+		problems = append(problems, "customer_id must be up to 256 characters and not blank")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidFilter, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// query encodes the filter as filter[...] query parameters
+func (f *Filter) query(query url.Values) {
+	if f == nil {
+		return
+	}
+
+	params := map[string]string{
+		"filter[bank_id]":        f.BankID,
+		"filter[bank_id_code]":   f.BankIDCode,
+		"filter[account_number]": f.AccountNumber,
+		"filter[iban]":           f.IBAN,
+		"filter[customer_id]":    f.CustomerID,
+		"filter[country]":        f.Country,
+	}
+
+	for key, value := range params {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+}
+
+// validate checks the options before they are sent to the API
+func (o *ListOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+
+	return o.Filter.Validate()
+}
+
+// query encodes the options as page[number], page[size] and filter[...] query parameters
+func (o *ListOptions) query() url.Values {
+	query := url.Values{}
+
+	if o == nil {
+		return query
+	}
+
+	if o.PageNumber > 0 {
+		query.Set("page[number]", strconv.Itoa(o.PageNumber))
+	}
+
+	if o.PageSize > 0 {
+		query.Set("page[size]", strconv.Itoa(o.PageSize))
+	}
+
+	o.Filter.query(query)
+
+	return query
+}
+
+// AccountIterator walks the pages of a list request lazily. A page is only requested when the accounts
+// of the previous one have been consumed
+//
+//	it := f3.Accounts.ListAll(&accounts.ListOptions{PageSize: 100})
+//	for it.Next() {
+//		account := it.Account()
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type AccountIterator struct {
+	items   *resource.Iterator
+	current model.Account
+	// err is the error of the options, which stops the iteration before any request
+	err error
+}
+
+// Next advances the iterator to the next account, requesting the next page when needed. It returns false
+// when there are no more accounts or when a request failed
+func (it *AccountIterator) Next() bool {
+	return it.err == nil && it.items.Next(&it.current)
+}
+
+// Account returns the account the iterator is currently at
+func (it *AccountIterator) Account() model.Account {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *AccountIterator) Err() error {
+	if it.err != nil {
+		return it.err
+	}
+
+	return it.items.Err()
+}
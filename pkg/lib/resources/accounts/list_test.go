@@ -0,0 +1,51 @@
+package accounts_test
+
+import (
+	"errors"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFilter_Validate(t *testing.T) {
+
+	t.Run("should accept a valid filter", func(t *testing.T) {
+		filter := &accounts.Filter{
+			BankID:        "400300",
+			BankIDCode:    "GBDSC",
+			AccountNumber: "41426819",
+			IBAN:          "GB16NWBK40030041426819",
+			CustomerID:    "customer-1",
+			Country:       "GB",
+		}
+
+		assert.Nil(t, filter.Validate())
+	})
+
+	t.Run("should accept an empty or nil filter", func(t *testing.T) {
+		var filter *accounts.Filter
+
+		assert.Nil(t, filter.Validate())
+		assert.Nil(t, (&accounts.Filter{}).Validate())
+	})
+
+	t.Run("should report all the invalid fields", func(t *testing.T) {
+		filter := &accounts.Filter{
+			BankID:        "400 300",
+			BankIDCode:    "gbdsc",
+			AccountNumber: "4142-6819",
+			IBAN:          "not an iban",
+			CustomerID:    strings.Repeat("a", 257),
+			Country:       "GBR",
+		}
+
+		err := filter.Validate()
+
+		assert.True(t, errors.Is(err, accounts.ErrInvalidFilter))
+
+		for _, field := range []string{"bank_id ", "bank_id_code", "account_number", "iban", "customer_id", "country"} {
+			assert.Contains(t, err.Error(), field)
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package accounts
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/iban"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidAccount is wrapped by the ValidationError of an account that fails the client side validation
+var ErrInvalidAccount = errors.New("accounts: invalid account")
+
+// FieldError is a problem with a field of an account. Field is the JSON name of the attribute
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Error returns the field followed by the problem
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// ValidationError holds all the problems found in an account. It wraps ErrInvalidAccount and is reported by
+// client.IsValidation
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error lists all the problems
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Errors))
+
+	for i, fieldErr := range e.Errors {
+		problems[i] = fieldErr.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidAccount, strings.Join(problems, "; "))
+}
+
+// Unwrap returns ErrInvalidAccount
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidAccount
+}
+
+// Is makes the error match client.ErrValidation too, like a validation failure reported by the API
+func (e *ValidationError) Is(target error) bool {
+	return target == client.ErrValidation
+}
+
+// countryRule holds the bank details Form3 accepts for the accounts of a country
+type countryRule struct {
+	// bankID is the format of bank_id. Nil means bank_id isn't supported
+	bankID         *regexp.Regexp
+	bankIDRequired bool
+	// bankIDCode is the only bank_id_code accepted. Empty means bank_id_code isn't supported
+	bankIDCode    string
+	bicRequired   bool
+	accountNumber *regexp.Regexp
+	// iban tells whether the country has IBANs
+	iban bool
+}
+
+// countryRules are the rules of the countries Form3 supports accounts for. Accounts of other ISO 3166
+// countries only get the generic checks. The bank details of the countries with IBANs follow their BBAN layout
+var countryRules = map[string]countryRule{
+	"AU": {bankID: digits(6, 6), bankIDCode: "AUBSB", bicRequired: true, accountNumber: digits(6, 10)},
+	"BE": ibanRule("BE", "BE", false),
+	"CA": {bankID: regexp.MustCompile(`^0[0-9]{8}$`), bankIDCode: "CACPA", bicRequired: true, accountNumber: digits(7, 12)},
+	"CH": ibanRule("CH", "CHBCC", false),
+	"DE": ibanRule("DE", "DEBLZ", false),
+	"ES": ibanRule("ES", "ESNCC", false),
+	"FR": ibanRule("FR", "FR", false),
+	"GB": ibanRule("GB", "GBDSC", true),
+	"GR": ibanRule("GR", "GRBIC", false),
+	"HK": {bankID: digits(3, 3), bankIDCode: "HKNCC", bicRequired: true, accountNumber: digits(9, 12)},
+	"IT": ibanRule("IT", "ITNCC", false),
+	"LU": ibanRule("LU", "LULUX", false),
+	"NL": ibanRule("NL", "", true),
+	"PL": ibanRule("PL", "PLKNR", false),
+	"PT": ibanRule("PT", "PTNCC", false),
+	"US": {bankID: digits(9, 9), bankIDRequired: true, bankIDCode: "USABA", bicRequired: true, accountNumber: digits(6, 17)},
+}
+
+// digits returns a pattern matching min to max digits
+func digits(min int, max int) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`^[0-9]{%d,%d}$`, min, max))
+}
+
+// ibanRule returns the rule of a country with IBANs. The bank ID and the account number must fit the BBAN layout
+// of the country, so an IBAN can be built from them and broken down into them
+func ibanRule(country string, bankIDCode string, bicRequired bool) countryRule {
+	layout, ok := iban.LayoutOf(country)
+
+	if !ok {
+		panic(fmt.Sprintf("accounts: no BBAN layout for %s", country))
+	}
+
+	rule := countryRule{
+		bankIDCode:    bankIDCode,
+		bicRequired:   bicRequired,
+		accountNumber: regexp.MustCompile(layout.AccountNumber.Pattern()),
+		iban:          true,
+	}
+
+	if layout.BankID.Length() > 0 {
+		rule.bankID = regexp.MustCompile(layout.BankID.Pattern())
+		rule.bankIDRequired = true
+	}
+
+	return rule
+}
+
+// ValidateCreateRequest checks an account before it is created, like the API does: the identifiers, the ISO 3166
+// country, the ISO 4217 base currency, the BIC, the IBAN checksum and the bank details required by the country of
+// the account. All the problems are reported in a single *ValidationError
+func ValidateCreateRequest(request *model.AccountCreateRequest) error {
+	if errs := validateAccount(request.Data, true); len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+
+	return nil
+}
+
+// validateAccount does the checks of ValidateCreateRequest and returns the problems found. The organisation ID
+// is only required with requireOrganisation, as a service can set a default one
+func validateAccount(account model.Account, requireOrganisation bool) []FieldError {
+	v := &validation{}
+	attributes := account.Attributes
+
+	if account.ID == uuid.Nil {
+		v.add("id", "is required")
+	}
+
+	if account.OrganisationID == uuid.Nil && requireOrganisation {
+		v.add("organisation_id", "is required")
+	}
+
+	if account.Type != "accounts" {
+		v.add("type", "must be accounts")
+	}
+
+	v.validateNames(attributes)
+
+	switch {
+	case attributes.Country == "":
+		v.add("country", "is required")
+	case !IsCountryCode(attributes.Country):
+		v.add("country", fmt.Sprintf("%q must be an ISO 3166-1 alpha-2 code", attributes.Country))
+	}
+
+	if attributes.BaseCurrency != "" && !IsCurrencyCode(attributes.BaseCurrency) {
+		v.add("base_currency", fmt.Sprintf("%q must be an ISO 4217 code", attributes.BaseCurrency))
+	}
+
+	if attributes.Bic != "" && !iban.IsValidBIC(attributes.Bic) {
+		v.add("bic", fmt.Sprintf("%q must be 8 or 11 characters with a letter bank and country code", attributes.Bic))
+	}
+
+	if attributes.Iban != "" && (iban.Normalize(attributes.Iban) != attributes.Iban || !iban.IsValid(attributes.Iban)) {
+		v.add("iban", fmt.Sprintf("%q must be an IBAN in electronic format with a valid checksum", attributes.Iban))
+	}
+
+	switch attributes.AccountClassification {
+	case "", model.AccountClassificationPersonal, model.AccountClassificationBusiness:
+	default:
+		v.add("account_classification", fmt.Sprintf("must be %s or %s", model.AccountClassificationPersonal, model.AccountClassificationBusiness))
+	}
+
+	if rule, ok := countryRules[attributes.Country]; ok {
+		v.validateCountry(attributes, rule)
+	}
+
+	return v.errors
+}
+
+// validation collects the problems of an account
+type validation struct {
+	errors []FieldError
+}
+
+func (v *validation) add(field string, message string) {
+	v.errors = append(v.errors, FieldError{Field: field, Message: message})
+}
+
+// validateNames checks the name and the alternative names of the account holder
+func (v *validation) validateNames(attributes model.AccountAttributes) {
+	if len(attributes.Name) == 0 || len(attributes.Name) > 4 {
+		v.add("name", "must have between 1 and 4 items")
+	}
+
+	if len(attributes.AlternativeNames) > 3 {
+		v.add("alternative_names", "must have up to 3 items")
+	}
+
+	for _, name := range append(append([]string(nil), attributes.Name...), attributes.AlternativeNames...) {
+		if strings.TrimSpace(name) == "" || len(name) > 140 {
+			v.add("name", "items must be up to 140 characters and not blank")
+			return
+		}
+	}
+}
+
+// validateCountry checks the bank details against the rule of the country of the account
+func (v *validation) validateCountry(attributes model.AccountAttributes, rule countryRule) {
+	country := attributes.Country
+
+	switch {
+	case rule.bankID == nil && attributes.BankID != "":
+		v.add("bank_id", fmt.Sprintf("is not supported for %s", country))
+	case attributes.BankID == "" && rule.bankIDRequired:
+		v.add("bank_id", fmt.Sprintf("is required for %s", country))
+	case attributes.BankID != "" && !rule.bankID.MatchString(attributes.BankID):
+		v.add("bank_id", fmt.Sprintf("%q must match %s for %s", attributes.BankID, rule.bankID, country))
+	}
+
+	switch {
+	case rule.bankIDCode == "" && attributes.BankIDCode != "":
+		v.add("bank_id_code", fmt.Sprintf("is not supported for %s", country))
+	case rule.bankIDCode != "" && attributes.BankIDCode != rule.bankIDCode && (attributes.BankIDCode != "" || attributes.BankID != ""):
+		v.add("bank_id_code", fmt.Sprintf("must be %s for %s", rule.bankIDCode, country))
+	}
+
+	if rule.bicRequired && attributes.Bic == "" {
+		v.add("bic", fmt.Sprintf("is required for %s", country))
+	}
+
+	if attributes.AccountNumber != "" && !rule.accountNumber.MatchString(attributes.AccountNumber) {
+		v.add("account_number", fmt.Sprintf("%q must match %s for %s", attributes.AccountNumber, rule.accountNumber, country))
+	}
+
+	switch {
+	case !rule.iban && attributes.Iban != "":
+		v.add("iban", fmt.Sprintf("is not supported for %s", country))
+	case rule.iban && attributes.Iban != "" && !strings.HasPrefix(attributes.Iban, country):
+		v.add("iban", fmt.Sprintf("must be a %s IBAN", country))
+	}
+}
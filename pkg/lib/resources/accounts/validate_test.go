@@ -0,0 +1,177 @@
+package accounts_test
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/iban"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/accounts"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// Returns the fields of the problems reported by the validation of an account
+func invalidFields(t *testing.T, err error) []string {
+	var validationErr *accounts.ValidationError
+	require.True(t, errors.As(err, &validationErr), "%v", err)
+
+	var fields []string
+	for _, fieldErr := range validationErr.Errors {
+		fields = append(fields, fieldErr.Field)
+	}
+
+	return fields
+}
+
+func TestValidateCreateRequest(t *testing.T) {
+
+	t.Run("should accept a valid account", func(t *testing.T) {
+		assert.Nil(t, accounts.ValidateCreateRequest(testUtils.GetAccountCreateRequest(uuid.New())))
+	})
+
+	t.Run("should accept the bank details of the supported countries", func(t *testing.T) {
+		for _, attributes := range []model.AccountAttributes{
+			{Country: "GB", BankID: "400300", BankIDCode: "GBDSC", Bic: "NWBKGB22", AccountNumber: "41426819", Iban: "GB16NWBK40030041426819"},
+			{Country: "DE", BankID: "37040044", BankIDCode: "DEBLZ", AccountNumber: "0532013000"},
+			{Country: "FR", BankID: "2004101005", BankIDCode: "FR", AccountNumber: "0500013M026"},
+			{Country: "ES", BankID: "21000418", BankIDCode: "ESNCC", AccountNumber: "0200051332"},
+			{Country: "IT", BankID: "0542811101", BankIDCode: "ITNCC", AccountNumber: "000000123456"},
+			{Country: "AU", BankID: "033000", BankIDCode: "AUBSB", Bic: "WPACAU2S", AccountNumber: "12345678"},
+			{Country: "CA", BankID: "012345678", BankIDCode: "CACPA", Bic: "ROYCCAT2", AccountNumber: "1234567"},
+			{Country: "US", BankID: "021000021", BankIDCode: "USABA", Bic: "CHASUS33", AccountNumber: "123456789"},
+			{Country: "NL", Bic: "ABNANL2A", AccountNumber: "0417164300"},
+			{Country: "JP"},
+		} {
+			account := testUtils.GetAccountCreateRequest(uuid.New())
+			attributes.Name = []string{"Samantha Holder"}
+			account.Data.Attributes = attributes
+
+			assert.Nil(t, accounts.ValidateCreateRequest(account), attributes.Country)
+		}
+	})
+
+	t.Run("should accept the bank details of an IBAN and build the same IBAN from them", func(t *testing.T) {
+		for _, example := range []struct {
+			iban       string
+			bankIDCode string
+			bic        string
+		}{
+			{"BE68539007547034", "BE", ""},
+			{"CH9300762011623852957", "CHBCC", ""},
+			{"DE89370400440532013000", "DEBLZ", ""},
+			{"ES9121000418450200051332", "ESNCC", ""},
+			{"FR1420041010050500013M02606", "FR", ""},
+			{"GB29NWBK60161331926819", "GBDSC", "NWBKGB2L"},
+			{"GR1601101250000000012300695", "GRBIC", ""},
+			{"IT60X0542811101000000123456", "ITNCC", ""},
+			{"LU280019400644750000", "LULUX", ""},
+			{"NL91ABNA0417164300", "", "ABNANL2A"},
+			{"PL61109010140000071219812874", "PLKNR", ""},
+			{"PT50000201231234567890154", "PTNCC", ""},
+		} {
+			t.Run(example.iban[:2], func(t *testing.T) {
+				account := testUtils.GetAccountCreateRequest(uuid.New())
+				account.Data.Attributes = model.AccountAttributes{
+					Name:       []string{"Samantha Holder"},
+					BankIDCode: example.bankIDCode,
+					Bic:        example.bic,
+				}
+				require.NoError(t, accounts.SetBankDetailsFromIBAN(&account.Data.Attributes, example.iban))
+
+				assert.Nil(t, accounts.ValidateCreateRequest(account))
+
+				attributes := account.Data.Attributes
+				built, err := iban.Build(attributes.Country, attributes.BankID, attributes.Bic, attributes.AccountNumber)
+				require.NoError(t, err)
+				assert.Equal(t, example.iban, built)
+			})
+		}
+	})
+
+	t.Run("should report all the problems at once", func(t *testing.T) {
+		account := &model.AccountCreateRequest{Data: model.Account{
+			Attributes: model.AccountAttributes{
+				Country:               "XX",
+				BaseCurrency:          "GBX",
+				Bic:                   "NW-BK",
+				AccountClassification: "Corporate",
+			},
+		}}
+
+		err := accounts.ValidateCreateRequest(account)
+
+		assert.True(t, errors.Is(err, accounts.ErrInvalidAccount))
+		assert.True(t, client.IsValidation(err))
+		assert.Equal(t, []string{"id", "organisation_id", "type", "name", "country", "base_currency", "bic", "account_classification"}, invalidFields(t, err))
+		assert.Contains(t, err.Error(), `country "XX" must be an ISO 3166-1 alpha-2 code`)
+	})
+
+	t.Run("should require a country", func(t *testing.T) {
+		account := testUtils.GetAccountCreateRequest(uuid.New())
+		account.Data.Attributes.Country = ""
+
+		assert.Equal(t, []string{"country"}, invalidFields(t, accounts.ValidateCreateRequest(account)))
+	})
+
+	t.Run("should apply the rules of GB", func(t *testing.T) {
+		account := testUtils.GetAccountCreateRequest(uuid.New())
+		account.Data.Attributes.BankID = "40030"
+		account.Data.Attributes.BankIDCode = "DEBLZ"
+		account.Data.Attributes.Bic = ""
+		account.Data.Attributes.AccountNumber = "4142681"
+		account.Data.Attributes.Iban = "DE89370400440532013000"
+
+		err := accounts.ValidateCreateRequest(account)
+
+		assert.Equal(t, []string{"bank_id", "bank_id_code", "bic", "account_number", "iban"}, invalidFields(t, err))
+	})
+
+	t.Run("should apply the rules of DE", func(t *testing.T) {
+		account := testUtils.GetAccountCreateRequest(uuid.New())
+		account.Data.Attributes = model.AccountAttributes{Country: "DE", Name: []string{"Samantha Holder"}}
+
+		assert.Equal(t, []string{"bank_id"}, invalidFields(t, accounts.ValidateCreateRequest(account)))
+	})
+
+	t.Run("should reject the IBAN and bank details of countries that don't support them", func(t *testing.T) {
+		account := testUtils.GetAccountCreateRequest(uuid.New())
+		account.Data.Attributes = model.AccountAttributes{
+			Country:    "NL",
+			Name:       []string{"Samantha Holder"},
+			Bic:        "ABNANL2A",
+			BankID:     "ABNA",
+			BankIDCode: "NLBIC",
+		}
+
+		assert.Equal(t, []string{"bank_id", "bank_id_code"}, invalidFields(t, accounts.ValidateCreateRequest(account)))
+
+		account.Data.Attributes = model.AccountAttributes{
+			Country:    "US",
+			Name:       []string{"Samantha Holder"},
+			Bic:        "CHASUS33",
+			BankID:     "021000021",
+			BankIDCode: "USABA",
+			Iban:       "DE89370400440532013000",
+		}
+
+		assert.Equal(t, []string{"iban"}, invalidFields(t, accounts.ValidateCreateRequest(account)))
+	})
+
+	t.Run("should check the IBAN checksum", func(t *testing.T) {
+		account := testUtils.GetAccountCreateRequest(uuid.New())
+		account.Data.Attributes.Iban = "GB11NWBK40030041426819"
+
+		assert.Equal(t, []string{"iban"}, invalidFields(t, accounts.ValidateCreateRequest(account)))
+	})
+
+	t.Run("should reject blank and too many names", func(t *testing.T) {
+		account := testUtils.GetAccountCreateRequest(uuid.New())
+		account.Data.Attributes.Name = []string{" "}
+		account.Data.Attributes.AlternativeNames = []string{"a", "b", "c", "d"}
+
+		assert.Equal(t, []string{"alternative_names", "name"}, invalidFields(t, accounts.ValidateCreateRequest(account)))
+	})
+}
@@ -0,0 +1,38 @@
+package organisations
+
+import (
+	"github.com/google/uuid"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions holds the pagination parameters of a list request. Zero values are left to the API defaults
+type ListOptions struct {
+	PageNumber int
+	PageSize   int
+	// ParentOrganisationID only returns the organisation units that belong to this organisation, if set
+	ParentOrganisationID uuid.UUID
+}
+
+// Query encodes the options as the page[number], page[size] and filter[...] parameters of List and ListAll
+func (o *ListOptions) Query() url.Values {
+	query := url.Values{}
+
+	if o == nil {
+		return query
+	}
+
+	if o.PageNumber > 0 {
+		query.Set("page[number]", strconv.Itoa(o.PageNumber))
+	}
+
+	if o.PageSize > 0 {
+		query.Set("page[size]", strconv.Itoa(o.PageSize))
+	}
+
+	if o.ParentOrganisationID != uuid.Nil {
+		query.Set("filter[organisation_id]", o.ParentOrganisationID.String())
+	}
+
+	return query
+}
@@ -0,0 +1,32 @@
+// Package organisations manages the Form3 organisation units the accounts belong to. The service is a
+// resource.Service: the bodies are model.OrganisationCreateRequest and model.OrganisationUpdateRequest and the
+// organisation units are decoded into model.OrganisationApiResponse, model.OrganisationListResponse and, when
+// iterating, model.Organisation
+package organisations
+
+import (
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/resource"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
+)
+
+var (
+	// ErrMissingOrganisationID is returned by CreateOrGet when the organisation unit has no client-generated ID
+	ErrMissingOrganisationID = resource.ErrMissingID
+	// ErrInvalidVersion is returned by Delete and Update when the version is negative
+	ErrInvalidVersion = resource.ErrInvalidVersion
+)
+
+// Defines the Organisations interface
+type Form3Organisations interface {
+	resource.Operations
+}
+
+// NewForm3OrganisationsService creates the service of the organisation units at oe, e.g. v1/organisation/units/.
+// The options are the ones of the resource.Endpoint, e.g. resource.WithDefaultOrganisationID sets the parent
+// organisation of the organisation units created without one
+func NewForm3OrganisationsService(cl client.Form3ResourcesClient, oe string, opts ...resource.Option) *resource.Service {
+	opts = append([]resource.Option{resource.WithIDAttribute(tracing.AttributeOrganisationID)}, opts...)
+
+	return resource.NewService(resource.NewEndpoint(cl, "organisations", oe, opts...))
+}
@@ -0,0 +1,302 @@
+package organisations_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/organisations"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/resource"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// Implements Form3ResourcesClient interface. This struct is used to mock the Form3RestClient
+type mockedHttpClient struct {
+	MockGet    func(ctx context.Context, path string) ([]byte, error)
+	MockDelete func(ctx context.Context, path string) error
+	MockPost   func(ctx context.Context, path string, body []byte) ([]byte, error)
+	MockPatch  func(ctx context.Context, path string, body []byte) ([]byte, error)
+}
+
+func (cl *mockedHttpClient) Patch(path string, body []byte) ([]byte, error) {
+	return cl.PatchWithContext(context.Background(), path, body)
+}
+
+func (cl *mockedHttpClient) PatchWithContext(ctx context.Context, path string, body []byte) ([]byte, error) {
+	return cl.MockPatch(ctx, path, body)
+}
+
+func (cl *mockedHttpClient) Post(path string, body []byte) ([]byte, error) {
+	return cl.PostWithContext(context.Background(), path, body)
+}
+
+func (cl *mockedHttpClient) Delete(path string) error {
+	return cl.DeleteWithContext(context.Background(), path)
+}
+
+func (cl *mockedHttpClient) Get(path string) ([]byte, error) {
+	return cl.GetWithContext(context.Background(), path)
+}
+
+func (cl *mockedHttpClient) PostWithContext(ctx context.Context, path string, body []byte) ([]byte, error) {
+	return cl.MockPost(ctx, path, body)
+}
+
+func (cl *mockedHttpClient) DeleteWithContext(ctx context.Context, path string) error {
+	return cl.MockDelete(ctx, path)
+}
+
+func (cl *mockedHttpClient) GetWithContext(ctx context.Context, path string) ([]byte, error) {
+	return cl.MockGet(ctx, path)
+}
+
+func TestForm3OrganisationsService_Fetch(t *testing.T) {
+
+	organisationID := uuid.New()
+
+	t.Run("should return an OrganisationApiResponse", func(t *testing.T) {
+		expectedResponse := testUtils.GetOrganisationApiResponse(organisationID)
+
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				assert.Equal(t, "path/to/units/endpoint/"+organisationID.String(), path)
+				return json.Marshal(expectedResponse)
+			},
+		}, "path/to/units/endpoint/")
+
+		var response model.OrganisationApiResponse
+		err := organisationsService.Fetch(organisationID, &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, *expectedResponse, response)
+	})
+
+	t.Run("should return an error if the client fails", func(t *testing.T) {
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return nil, &client.APIError{StatusCode: http.StatusNotFound}
+			},
+		}, "path/to/units/endpoint/")
+
+		err := organisationsService.Fetch(organisationID, &model.OrganisationApiResponse{})
+
+		assert.True(t, client.IsNotFound(err))
+	})
+}
+
+func TestForm3OrganisationsService_Create(t *testing.T) {
+
+	organisationID := uuid.New()
+
+	t.Run("should create an organisation unit", func(t *testing.T) {
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				assert.Equal(t, "path/to/units/endpoint/", path)
+				return json.Marshal(testUtils.GetOrganisationApiResponse(organisationID))
+			},
+		}, "path/to/units/endpoint/")
+
+		var response model.OrganisationApiResponse
+		err := organisationsService.Create(testUtils.GetOrganisationCreateRequest(organisationID), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, *testUtils.GetOrganisationApiResponse(organisationID), response)
+	})
+
+	t.Run("should set the default parent organisation if the organisation unit has none", func(t *testing.T) {
+		parentID := uuid.New()
+		organisationToCreate := testUtils.GetOrganisationCreateRequest(organisationID)
+		organisationToCreate.Data.OrganisationID = uuid.Nil
+
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				var sent model.OrganisationCreateRequest
+				require.NoError(t, json.Unmarshal(body, &sent))
+				assert.Equal(t, parentID, sent.Data.OrganisationID)
+				return json.Marshal(testUtils.GetOrganisationApiResponse(organisationID))
+			},
+		}, "path/to/units/endpoint/", resource.WithDefaultOrganisationID(parentID))
+
+		err := organisationsService.Create(organisationToCreate, &model.OrganisationApiResponse{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, uuid.Nil, organisationToCreate.Data.OrganisationID)
+	})
+
+	t.Run("should return the existing organisation unit on create or get", func(t *testing.T) {
+		existing := testUtils.GetOrganisationApiResponse(organisationID)
+		existing.Data.Attributes.Name = "Someone Else Ltd"
+
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return nil, &client.APIError{StatusCode: http.StatusConflict}
+			},
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return json.Marshal(existing)
+			},
+		}, "path/to/units/endpoint/")
+
+		var response model.OrganisationApiResponse
+		created, err := organisationsService.CreateOrGet(testUtils.GetOrganisationCreateRequest(organisationID), &response)
+
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Equal(t, *existing, response)
+	})
+
+	t.Run("should require an ID on create or get", func(t *testing.T) {
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{}, "path/to/units/endpoint/")
+
+		_, err := organisationsService.CreateOrGet(testUtils.GetOrganisationCreateRequest(uuid.Nil), &model.OrganisationApiResponse{})
+
+		assert.True(t, errors.Is(err, organisations.ErrMissingOrganisationID))
+	})
+}
+
+func TestForm3OrganisationsService_Update(t *testing.T) {
+
+	organisationID := uuid.New()
+
+	t.Run("should patch the organisation unit", func(t *testing.T) {
+		expectedResponse := testUtils.GetOrganisationApiResponse(organisationID)
+		expectedResponse.Data.Version = 1
+
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockPatch: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				assert.Equal(t, "path/to/units/endpoint/"+organisationID.String(), path)
+				return json.Marshal(expectedResponse)
+			},
+		}, "path/to/units/endpoint/")
+
+		var response model.OrganisationApiResponse
+		err := organisationsService.Update(&model.OrganisationUpdateRequest{Data: testUtils.GetOrganisationApiResponse(organisationID).Data}, &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, *expectedResponse, response)
+	})
+
+	t.Run("should not call the API if the version is negative", func(t *testing.T) {
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{}, "path/to/units/endpoint/")
+		organisationToUpdate := &model.OrganisationUpdateRequest{Data: testUtils.GetOrganisationApiResponse(organisationID).Data}
+		organisationToUpdate.Data.Version = -1
+
+		err := organisationsService.Update(organisationToUpdate, &model.OrganisationApiResponse{})
+
+		assert.True(t, errors.Is(err, organisations.ErrInvalidVersion))
+	})
+}
+
+func TestForm3OrganisationsService_Delete(t *testing.T) {
+
+	organisationID := uuid.New()
+
+	t.Run("should send the version", func(t *testing.T) {
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockDelete: func(ctx context.Context, path string) error {
+				assert.Equal(t, "path/to/units/endpoint/"+organisationID.String()+"?version=2", path)
+				return nil
+			},
+		}, "path/to/units/endpoint/")
+
+		assert.Nil(t, organisationsService.Delete(organisationID, 2))
+	})
+
+	t.Run("should treat a missing organisation unit as deleted when enabled", func(t *testing.T) {
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return nil, &client.APIError{StatusCode: http.StatusNotFound}
+			},
+		}, "path/to/units/endpoint/", resource.WithNotFoundAsDeleted())
+
+		assert.Nil(t, organisationsService.DeleteLatest(organisationID, 3))
+	})
+}
+
+func TestForm3OrganisationsService_List(t *testing.T) {
+
+	parentID := uuid.New()
+
+	t.Run("should request a page of the organisation units of a parent", func(t *testing.T) {
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				u, err := url.Parse(path)
+				require.NoError(t, err)
+				assert.Equal(t, "path/to/units/endpoint", u.Path)
+				assert.Equal(t, url.Values{
+					"page[number]":            {"1"},
+					"page[size]":              {"20"},
+					"filter[organisation_id]": {parentID.String()},
+				}, u.Query())
+				return json.Marshal(model.OrganisationListResponse{Data: []model.Organisation{testUtils.GetOrganisationApiResponse(uuid.New()).Data}})
+			},
+		}, "path/to/units/endpoint/")
+
+		var response model.OrganisationListResponse
+		listOptions := &organisations.ListOptions{PageNumber: 1, PageSize: 20, ParentOrganisationID: parentID}
+		err := organisationsService.List(listOptions.Query(), &response)
+
+		assert.Nil(t, err)
+		assert.Len(t, response.Data, 1)
+	})
+
+	t.Run("should walk all the pages", func(t *testing.T) {
+		var requestedPages []string
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				u, err := url.Parse(path)
+				require.NoError(t, err)
+				requestedPages = append(requestedPages, u.Query().Get("page[number]"))
+				response := model.OrganisationListResponse{Data: []model.Organisation{testUtils.GetOrganisationApiResponse(uuid.New()).Data}}
+
+				if len(requestedPages) < 2 {
+					response.Links.Next = "next"
+				}
+
+				return json.Marshal(response)
+			},
+		}, "path/to/units/endpoint/")
+
+		it := organisationsService.ListAll(nil)
+		count := 0
+		var organisation model.Organisation
+
+		for it.Next(&organisation) {
+			assert.Equal(t, "organisations", organisation.Type)
+			count++
+		}
+
+		assert.Nil(t, it.Err())
+		assert.Equal(t, 2, count)
+		assert.Equal(t, []string{"", "1"}, requestedPages)
+	})
+}
+
+func TestForm3OrganisationsService_WithTracer(t *testing.T) {
+
+	organisationID := uuid.New()
+
+	t.Run("should record a span for every operation", func(t *testing.T) {
+		exporter := tracing.NewInMemoryExporter()
+		organisationsService := organisations.NewForm3OrganisationsService(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return json.Marshal(testUtils.GetOrganisationApiResponse(organisationID))
+			},
+		}, "path/to/units/endpoint/", resource.WithTracer(tracing.NewTracer(exporter)))
+
+		err := organisationsService.Fetch(organisationID, &model.OrganisationApiResponse{})
+		require.NoError(t, err)
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "organisations.fetch", spans[0].Name)
+		assert.Equal(t, organisationID.String(), spans[0].Attributes[tracing.AttributeOrganisationID])
+	})
+}
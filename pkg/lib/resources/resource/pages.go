@@ -0,0 +1,147 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// Pages requests the pages of a list request one at a time. It is the core of Iterator
+type Pages struct {
+	ctx      context.Context
+	endpoint *Endpoint
+	query    url.Values
+	number   int
+	started  bool
+	last     bool
+	err      error
+}
+
+// Pages returns the pages of a list request, starting from the page[number] of query, if any
+func (e *Endpoint) Pages(ctx context.Context, query url.Values) *Pages {
+	pages := &Pages{
+		ctx:      ctx,
+		endpoint: e,
+		query:    url.Values{},
+	}
+
+	for key, values := range query {
+		pages.query[key] = append([]string(nil), values...)
+	}
+
+	pages.number, _ = strconv.Atoi(query.Get("page[number]"))
+
+	return pages
+}
+
+// pageEnvelope decodes what Pages needs to know of any page
+type pageEnvelope struct {
+	Data  []json.RawMessage `json:"data"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+// Next decodes the next page into out, a list envelope such as model.AccountListResponse. It returns false
+// when the last page has been requested or when a request failed
+func (p *Pages) Next(out interface{}) bool {
+	if p.err != nil || p.last {
+		return false
+	}
+
+	if p.started {
+		p.number++
+		p.query.Set("page[number]", strconv.Itoa(p.number))
+	}
+
+	p.started = true
+
+	var page json.RawMessage
+
+	if p.err = p.endpoint.List(p.ctx, p.query, &page); p.err != nil {
+		return false
+	}
+
+	var envelope pageEnvelope
+
+	if p.err = json.Unmarshal(page, &envelope); p.err != nil {
+		return false
+	}
+
+	p.last = envelope.Links.Next == "" || len(envelope.Data) == 0
+
+	if p.err = json.Unmarshal(page, out); p.err != nil {
+		return false
+	}
+
+	return true
+}
+
+// Err returns the error that stopped the pages, if any
+func (p *Pages) Err() error {
+	return p.err
+}
+
+// Iterator walks the resources of the pages of a list request one at a time. A page is only requested when the
+// resources of the previous one have been consumed
+//
+//	it := endpoint.Iterate(ctx, query)
+//	var account model.Account
+//	for it.Next(&account) {
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type Iterator struct {
+	pages *Pages
+	page  []json.RawMessage
+	err   error
+}
+
+// Iterate returns an Iterator over the resources of a list request, starting from the page[number] of query,
+// if any
+func (e *Endpoint) Iterate(ctx context.Context, query url.Values) *Iterator {
+	return &Iterator{pages: e.Pages(ctx, query)}
+}
+
+// Next decodes the next resource into item, a pointer to a resource of the model such as model.Account,
+// requesting the next page when needed. It returns false when there are no more resources or when a request
+// or the decoding failed
+func (it *Iterator) Next(item interface{}) bool {
+	for len(it.page) == 0 {
+		if it.err != nil {
+			return false
+		}
+
+		var envelope pageEnvelope
+
+		if !it.pages.Next(&envelope) {
+			it.err = it.pages.Err()
+			return false
+		}
+
+		it.page = envelope.Data
+	}
+
+	next := it.page[0]
+	it.page = it.page[1:]
+
+	// The fields a resource doesn't have would otherwise keep the values of the previous one
+	if value := reflect.ValueOf(item); value.Kind() == reflect.Ptr && !value.IsNil() {
+		value.Elem().Set(reflect.Zero(value.Elem().Type()))
+	}
+
+	if it.err = json.Unmarshal(next, item); it.err != nil {
+		return false
+	}
+
+	return true
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *Iterator) Err() error {
+	return it.err
+}
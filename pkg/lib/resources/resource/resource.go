@@ -0,0 +1,422 @@
+// Package resource is the core the Form3 resource services are built on. An Endpoint does the create, fetch,
+// list, update and delete requests of a resource type against its JSON:API collection endpoint, encoding and
+// decoding the envelope types of the model, e.g. model.AccountApiResponse, and records a span for every
+// operation. Go has no generics here, so the envelopes are passed as pointers. A Service adds the forms without
+// a context the services of this library have
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrMissingID is returned by CreateOrFetch when the resource has no client-generated ID
+	ErrMissingID = errors.New("resource: ID is required")
+	// ErrInvalidVersion is returned by Delete and Update when the version is negative
+	ErrInvalidVersion = model.ErrInvalidVersion
+)
+
+// Endpoint does the requests of a resource type
+type Endpoint struct {
+	client                client.Form3ResourcesClient
+	name                  string
+	path                  string
+	idAttribute           string
+	tracer                tracing.Tracer
+	notFoundAsDeleted     bool
+	defaultOrganisationID uuid.UUID
+}
+
+// Option configures optional behaviour of an Endpoint
+type Option func(e *Endpoint)
+
+// WithTracer sets the tracer that records a span for every operation
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(e *Endpoint) {
+		e.tracer = tracer
+	}
+}
+
+// WithIDAttribute sets the span attribute the ID of the resource is recorded with. Defaults to
+// tracing.AttributeResourceID
+func WithIDAttribute(key string) Option {
+	return func(e *Endpoint) {
+		e.idAttribute = key
+	}
+}
+
+// WithNotFoundAsDeleted makes the deletes of a resource that doesn't exist succeed
+func WithNotFoundAsDeleted() Option {
+	return func(e *Endpoint) {
+		e.notFoundAsDeleted = true
+	}
+}
+
+// WithDefaultOrganisationID sets the organisation of the resources created without one
+func WithDefaultOrganisationID(organisationID uuid.UUID) Option {
+	return func(e *Endpoint) {
+		e.defaultOrganisationID = organisationID
+	}
+}
+
+// NewEndpoint creates an Endpoint. The name prefixes the spans, e.g. accounts.fetch, and path is the collection
+// endpoint the resource IDs are appended to, e.g. v1/organisation/accounts/
+func NewEndpoint(cl client.Form3ResourcesClient, name string, path string, opts ...Option) *Endpoint {
+	e := &Endpoint{
+		client:      cl,
+		name:        name,
+		path:        path,
+		idAttribute: tracing.AttributeResourceID,
+		tracer:      tracing.NoopTracer(),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Fetch decodes the resource with the given ID into out
+func (e *Endpoint) Fetch(ctx context.Context, id uuid.UUID, out interface{}) error {
+	ctx, span := e.StartSpan(ctx, "fetch", id)
+	err := e.fetch(ctx, id, out)
+	EndSpan(span, err)
+
+	return err
+}
+
+// fetch does the request of Fetch
+func (e *Endpoint) fetch(ctx context.Context, id uuid.UUID, out interface{}) error {
+	responseBody, err := e.client.GetWithContext(ctx, e.resourcePath(id))
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(responseBody, out)
+}
+
+// List decodes a page of resources into out. The query holds the page[...] and filter[...] parameters
+func (e *Endpoint) List(ctx context.Context, query url.Values, out interface{}) error {
+	ctx, span := e.StartSpan(ctx, "list", uuid.Nil)
+	err := e.list(ctx, query, out)
+	EndSpan(span, err)
+
+	return err
+}
+
+// list does the request of List
+func (e *Endpoint) list(ctx context.Context, query url.Values, out interface{}) error {
+	path := strings.TrimSuffix(e.path, "/")
+
+	if encoded := query.Encode(); encoded != "" {
+		path = fmt.Sprintf("%s?%s", path, encoded)
+	}
+
+	responseBody, err := e.client.GetWithContext(ctx, path)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(responseBody, out)
+}
+
+// Create sends the create request body and decodes the created resource into out. On a duplicate ID conflict, the existing
+// resource is fetched into out if its organisation and attributes are the ones that were sent, e.g. because an
+// earlier attempt timed out after the resource was created. Otherwise the conflict is returned
+func (e *Endpoint) Create(ctx context.Context, id uuid.UUID, body interface{}, out interface{}) error {
+	ctx, span := e.StartSpan(ctx, "create", id)
+	_, err := e.createOrFetch(ctx, id, body, out, false)
+	EndSpan(span, err)
+
+	return err
+}
+
+// CreateOrFetch creates a resource or, if a resource with the same ID already exists, fetches it into out
+// whatever its attributes. It reports whether the resource was created. The ID must not be nil
+func (e *Endpoint) CreateOrFetch(ctx context.Context, id uuid.UUID, body interface{}, out interface{}) (bool, error) {
+	if id == uuid.Nil {
+		return false, ErrMissingID
+	}
+
+	ctx, span := e.StartSpan(ctx, "create_or_get", id)
+	created, err := e.createOrFetch(ctx, id, body, out, true)
+	EndSpan(span, err)
+
+	return created, err
+}
+
+// create does the request of Create
+func (e *Endpoint) create(ctx context.Context, body interface{}, out interface{}) error {
+	jsonBody, err := json.Marshal(body)
+
+	if err != nil {
+		return err
+	}
+
+	responseBody, err := e.client.PostWithContext(ctx, e.path, jsonBody)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(responseBody, out)
+}
+
+// createOrFetch creates a resource and reports whether it did. On a duplicate ID conflict, it fetches the
+// existing resource if anyAttributes is set or it is the one that was sent, otherwise it returns the conflict
+func (e *Endpoint) createOrFetch(ctx context.Context, id uuid.UUID, body interface{}, out interface{}, anyAttributes bool) (bool, error) {
+	body, err := e.withDefaultOrganisation(body)
+
+	if err != nil {
+		return false, err
+	}
+
+	err = e.create(ctx, body, out)
+
+	if err == nil {
+		return true, nil
+	}
+
+	if !client.IsConflict(err) || id == uuid.Nil {
+		return false, err
+	}
+
+	var existing json.RawMessage
+
+	if fetchErr := e.Fetch(ctx, id, &existing); fetchErr != nil {
+		// The conflict isn't about the ID, e.g. it is a duplicate account number
+		if client.IsNotFound(fetchErr) {
+			return false, err
+		}
+
+		return false, fetchErr
+	}
+
+	if !anyAttributes && !sameResource(body, existing) {
+		return false, err
+	}
+
+	return false, json.Unmarshal(existing, out)
+}
+
+// Update sends the update request body of the resource with the given ID at its current version and decodes
+// the updated resource into out
+func (e *Endpoint) Update(ctx context.Context, id uuid.UUID, version model.Version, body interface{}, out interface{}) error {
+	ctx, span := e.StartSpan(ctx, "update", id)
+	err := e.update(ctx, id, version, body, out)
+	EndSpan(span, err)
+
+	return err
+}
+
+// update does the request of Update
+func (e *Endpoint) update(ctx context.Context, id uuid.UUID, version model.Version, body interface{}, out interface{}) error {
+	if err := version.Validate(); err != nil {
+		return err
+	}
+
+	jsonBody, err := json.Marshal(body)
+
+	if err != nil {
+		return err
+	}
+
+	responseBody, err := e.client.PatchWithContext(ctx, e.resourcePath(id), jsonBody)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(responseBody, out)
+}
+
+// Delete deletes the resource with the given ID at its current version
+func (e *Endpoint) Delete(ctx context.Context, id uuid.UUID, version model.Version) error {
+	ctx, span := e.StartSpan(ctx, "delete", id)
+	err := e.delete(ctx, id, version)
+	EndSpan(span, err)
+
+	return err
+}
+
+// delete does the request of Delete
+func (e *Endpoint) delete(ctx context.Context, id uuid.UUID, version model.Version) error {
+	if err := version.Validate(); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("%s?%s", e.resourcePath(id), url.Values{"version": {version.String()}}.Encode())
+	err := e.client.DeleteWithContext(ctx, path)
+
+	if e.notFoundAsDeleted && client.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// versionEnvelope decodes the ID and the version of any resource
+type versionEnvelope struct {
+	Data struct {
+		ID      uuid.UUID     `json:"id"`
+		Version model.Version `json:"version"`
+	} `json:"data"`
+}
+
+// DeleteLatest fetches the current version of a resource and deletes it. If the delete fails with a version
+// conflict, the resource is fetched again, up to maxAttempts times in total
+func (e *Endpoint) DeleteLatest(ctx context.Context, id uuid.UUID, maxAttempts int) error {
+	ctx, span := e.StartSpan(ctx, "delete_latest", id)
+	attempts, err := e.deleteLatest(ctx, id, maxAttempts)
+	span.SetAttribute(tracing.AttributeAttempts, attempts)
+	EndSpan(span, err)
+
+	return err
+}
+
+// deleteLatest does the fetch and delete cycles of DeleteLatest and returns how many there were
+func (e *Endpoint) deleteLatest(ctx context.Context, id uuid.UUID, maxAttempts int) (int, error) {
+	for attempt := 1; ; attempt++ {
+		var current versionEnvelope
+		err := e.Fetch(ctx, id, &current)
+
+		if err != nil {
+			if e.notFoundAsDeleted && client.IsNotFound(err) {
+				return attempt, nil
+			}
+
+			return attempt, err
+		}
+
+		err = e.Delete(ctx, id, current.Data.Version)
+
+		if err == nil || !client.IsConflict(err) || attempt >= maxAttempts {
+			return attempt, err
+		}
+	}
+}
+
+// StartSpan starts the span of an operation, with the ID of the resource it is about when there is one. Meant
+// for the operations a service composes of several requests
+func (e *Endpoint) StartSpan(ctx context.Context, operation string, id uuid.UUID) (context.Context, tracing.Span) {
+	name := e.name + "." + operation
+	ctx, span := e.tracer.Start(ctx, name)
+	span.SetAttribute(tracing.AttributeOperation, name)
+
+	if id != uuid.Nil {
+		span.SetAttribute(e.idAttribute, id.String())
+	}
+
+	return ctx, span
+}
+
+// EndSpan ends the span of an operation with its error
+func EndSpan(span tracing.Span, err error) {
+	span.SetError(err)
+	span.End()
+}
+
+// withDefaultOrganisation returns the JSON of a create request body with the default organisation set, if the
+// resource has none. The body of the caller is left untouched
+func (e *Endpoint) withDefaultOrganisation(body interface{}) (interface{}, error) {
+	if e.defaultOrganisationID == uuid.Nil {
+		return body, nil
+	}
+
+	jsonBody, err := json.Marshal(body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The other fields are kept as they were encoded
+	var envelope, data map[string]json.RawMessage
+
+	if err := json.Unmarshal(jsonBody, &envelope); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(envelope["data"], &data); err != nil || data == nil {
+		return body, nil
+	}
+
+	var organisationID string
+
+	if raw, ok := data["organisation_id"]; ok {
+		if err := json.Unmarshal(raw, &organisationID); err != nil {
+			return nil, err
+		}
+	}
+
+	if organisationID != "" && organisationID != uuid.Nil.String() {
+		return body, nil
+	}
+
+	if data["organisation_id"], err = json.Marshal(e.defaultOrganisationID); err != nil {
+		return nil, err
+	}
+
+	if envelope["data"], err = json.Marshal(data); err != nil {
+		return nil, err
+	}
+
+	jsonBody, err = json.Marshal(envelope)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(jsonBody), nil
+}
+
+// resourcePath returns the path of the resource with the given ID
+func (e *Endpoint) resourcePath(id uuid.UUID) string {
+	return e.path + id.String()
+}
+
+// sameResource reports whether an existing resource is the one that was sent to be created. The organisation
+// must be the same and every attribute that was sent must have the same value, the attributes the API added
+// are ignored
+func sameResource(sent interface{}, existing json.RawMessage) bool {
+	jsonSent, err := json.Marshal(sent)
+
+	if err != nil {
+		return false
+	}
+
+	var sentEnvelope, existingEnvelope struct {
+		Data struct {
+			OrganisationID string                 `json:"organisation_id"`
+			Attributes     map[string]interface{} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if json.Unmarshal(jsonSent, &sentEnvelope) != nil || json.Unmarshal(existing, &existingEnvelope) != nil {
+		return false
+	}
+
+	if sentEnvelope.Data.OrganisationID != existingEnvelope.Data.OrganisationID {
+		return false
+	}
+
+	for key, value := range sentEnvelope.Data.Attributes {
+		if !reflect.DeepEqual(value, existingEnvelope.Data.Attributes[key]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,479 @@
+package resource_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/resource"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+// Implements Form3ResourcesClient interface. This struct is used to mock the Form3RestClient
+type mockedHttpClient struct {
+	MockGet    func(ctx context.Context, path string) ([]byte, error)
+	MockDelete func(ctx context.Context, path string) error
+	MockPost   func(ctx context.Context, path string, body []byte) ([]byte, error)
+	MockPatch  func(ctx context.Context, path string, body []byte) ([]byte, error)
+}
+
+func (cl *mockedHttpClient) Patch(path string, body []byte) ([]byte, error) {
+	return cl.PatchWithContext(context.Background(), path, body)
+}
+
+func (cl *mockedHttpClient) PatchWithContext(ctx context.Context, path string, body []byte) ([]byte, error) {
+	return cl.MockPatch(ctx, path, body)
+}
+
+func (cl *mockedHttpClient) Post(path string, body []byte) ([]byte, error) {
+	return cl.PostWithContext(context.Background(), path, body)
+}
+
+func (cl *mockedHttpClient) Delete(path string) error {
+	return cl.DeleteWithContext(context.Background(), path)
+}
+
+func (cl *mockedHttpClient) Get(path string) ([]byte, error) {
+	return cl.GetWithContext(context.Background(), path)
+}
+
+func (cl *mockedHttpClient) PostWithContext(ctx context.Context, path string, body []byte) ([]byte, error) {
+	return cl.MockPost(ctx, path, body)
+}
+
+func (cl *mockedHttpClient) DeleteWithContext(ctx context.Context, path string) error {
+	return cl.MockDelete(ctx, path)
+}
+
+func (cl *mockedHttpClient) GetWithContext(ctx context.Context, path string) ([]byte, error) {
+	return cl.MockGet(ctx, path)
+}
+
+// widget is the resource the endpoint is tested with
+type widget struct {
+	ID             uuid.UUID         `json:"id"`
+	OrganisationID uuid.UUID         `json:"organisation_id"`
+	Version        int               `json:"version"`
+	Attributes     map[string]string `json:"attributes"`
+}
+
+type widgetEnvelope struct {
+	Data widget `json:"data"`
+}
+
+type widgetListEnvelope struct {
+	Data  []widget `json:"data"`
+	Links struct {
+		Next string `json:"next,omitempty"`
+	} `json:"links"`
+}
+
+func newWidget(id uuid.UUID, name string) *widgetEnvelope {
+	return &widgetEnvelope{Data: widget{
+		ID:             id,
+		OrganisationID: uuid.MustParse("eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"),
+		Attributes:     map[string]string{"name": name},
+	}}
+}
+
+func TestEndpoint_Fetch(t *testing.T) {
+
+	widgetID := uuid.New()
+
+	t.Run("should decode the resource", func(t *testing.T) {
+		expected := newWidget(widgetID, "gear")
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				assert.Equal(t, "v1/widgets/"+widgetID.String(), path)
+				return json.Marshal(expected)
+			},
+		}, "widgets", "v1/widgets/")
+
+		var response widgetEnvelope
+		err := endpoint.Fetch(context.Background(), widgetID, &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, expected, &response)
+	})
+
+	t.Run("should return an error if the client fails", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return nil, &client.APIError{StatusCode: http.StatusNotFound}
+			},
+		}, "widgets", "v1/widgets/")
+
+		var response widgetEnvelope
+		err := endpoint.Fetch(context.Background(), widgetID, &response)
+
+		assert.True(t, client.IsNotFound(err))
+	})
+}
+
+func TestEndpoint_List(t *testing.T) {
+
+	t.Run("should request the collection with the query", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				assert.Equal(t, "v1/widgets?page%5Bsize%5D=2", path)
+				return json.Marshal(widgetListEnvelope{Data: []widget{newWidget(uuid.New(), "gear").Data}})
+			},
+		}, "widgets", "v1/widgets/")
+
+		var response widgetListEnvelope
+		err := endpoint.List(context.Background(), url.Values{"page[size]": {"2"}}, &response)
+
+		assert.Nil(t, err)
+		assert.Len(t, response.Data, 1)
+	})
+
+	t.Run("should not add a query without parameters", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				assert.Equal(t, "v1/widgets", path)
+				return json.Marshal(widgetListEnvelope{})
+			},
+		}, "widgets", "v1/widgets")
+
+		var response widgetListEnvelope
+		assert.Nil(t, endpoint.List(context.Background(), nil, &response))
+	})
+}
+
+func TestEndpoint_Pages(t *testing.T) {
+
+	// Returns 3 pages, the last one without a next link
+	pagedGet := func(requestedPages *[]string) func(ctx context.Context, path string) ([]byte, error) {
+		return func(ctx context.Context, path string) ([]byte, error) {
+			u, err := url.Parse(path)
+			require.NoError(t, err)
+			*requestedPages = append(*requestedPages, u.Query().Get("page[number]"))
+
+			response := widgetListEnvelope{Data: []widget{newWidget(uuid.New(), "gear").Data}}
+
+			if len(*requestedPages) < 3 {
+				response.Links.Next = "next"
+			}
+
+			return json.Marshal(response)
+		}
+	}
+
+	t.Run("should request the pages until the last one", func(t *testing.T) {
+		var requestedPages []string
+		endpoint := resource.NewEndpoint(&mockedHttpClient{MockGet: pagedGet(&requestedPages)}, "widgets", "v1/widgets/")
+
+		pages := endpoint.Pages(context.Background(), url.Values{"page[number]": {"4"}})
+		count := 0
+
+		for pages.Next(&widgetListEnvelope{}) {
+			count++
+		}
+
+		assert.Nil(t, pages.Err())
+		assert.Equal(t, 3, count)
+		assert.Equal(t, []string{"4", "5", "6"}, requestedPages)
+	})
+
+	t.Run("should stop and return the error if a page fails", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return nil, errors.New("there was an HTTP error")
+			},
+		}, "widgets", "v1/widgets/")
+
+		pages := endpoint.Pages(context.Background(), nil)
+
+		assert.False(t, pages.Next(&widgetListEnvelope{}))
+		assert.Equal(t, errors.New("there was an HTTP error"), pages.Err())
+	})
+}
+
+func TestEndpoint_Iterate(t *testing.T) {
+
+	t.Run("should walk the resources of all the pages", func(t *testing.T) {
+		var requestedPages []string
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				u, err := url.Parse(path)
+				require.NoError(t, err)
+				requestedPages = append(requestedPages, u.Query().Get("page[number]"))
+
+				if len(requestedPages) == 1 {
+					response := widgetListEnvelope{Data: []widget{newWidget(uuid.New(), "gear").Data, newWidget(uuid.New(), "cog").Data}}
+					response.Links.Next = "next"
+					return json.Marshal(response)
+				}
+
+				// A widget without attributes must not keep the ones of the previous widget
+				return json.Marshal(widgetListEnvelope{Data: []widget{{ID: uuid.New()}}})
+			},
+		}, "widgets", "v1/widgets/")
+
+		it := endpoint.Iterate(context.Background(), nil)
+		var names []string
+		var item widget
+
+		for it.Next(&item) {
+			names = append(names, item.Attributes["name"])
+		}
+
+		assert.Nil(t, it.Err())
+		assert.Equal(t, []string{"gear", "cog", ""}, names)
+		assert.Equal(t, []string{"", "1"}, requestedPages)
+	})
+
+	t.Run("should stop and return the error if a page fails", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return nil, errors.New("there was an HTTP error")
+			},
+		}, "widgets", "v1/widgets/")
+
+		it := endpoint.Iterate(context.Background(), nil)
+
+		assert.False(t, it.Next(&widget{}))
+		assert.Equal(t, errors.New("there was an HTTP error"), it.Err())
+	})
+}
+
+func TestEndpoint_Create(t *testing.T) {
+
+	widgetID := uuid.New()
+	conflict := &client.APIError{StatusCode: http.StatusConflict}
+
+	// Creates an endpoint whose resource already exists
+	newEndpoint := func(existing *widgetEnvelope) *resource.Endpoint {
+		return resource.NewEndpoint(&mockedHttpClient{
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return nil, conflict
+			},
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return json.Marshal(existing)
+			},
+		}, "widgets", "v1/widgets/")
+	}
+
+	t.Run("should post the body without an idempotency key of its own", func(t *testing.T) {
+		sent := newWidget(widgetID, "gear")
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				assert.Equal(t, "v1/widgets/", path)
+				assert.Empty(t, client.IdempotencyKeyFromContext(ctx))
+				return body, nil
+			},
+		}, "widgets", "v1/widgets/")
+
+		var response widgetEnvelope
+		err := endpoint.Create(context.Background(), widgetID, sent, &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, sent, &response)
+	})
+
+	t.Run("should return the existing resource if it is the one that was sent", func(t *testing.T) {
+		existing := newWidget(widgetID, "gear")
+		existing.Data.Attributes["status"] = "confirmed"
+
+		var response widgetEnvelope
+		err := newEndpoint(existing).Create(context.Background(), widgetID, newWidget(widgetID, "gear"), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, existing, &response)
+	})
+
+	t.Run("should return the conflict if the attributes are different", func(t *testing.T) {
+		var response widgetEnvelope
+		err := newEndpoint(newWidget(widgetID, "sprocket")).Create(context.Background(), widgetID, newWidget(widgetID, "gear"), &response)
+
+		assert.True(t, client.IsConflict(err))
+	})
+
+	t.Run("should return the existing resource whatever its attributes on create or fetch", func(t *testing.T) {
+		existing := newWidget(widgetID, "sprocket")
+
+		var response widgetEnvelope
+		created, err := newEndpoint(existing).CreateOrFetch(context.Background(), widgetID, newWidget(widgetID, "gear"), &response)
+
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Equal(t, existing, &response)
+	})
+
+	t.Run("should require an ID on create or fetch", func(t *testing.T) {
+		_, err := newEndpoint(nil).CreateOrFetch(context.Background(), uuid.Nil, newWidget(uuid.Nil, "gear"), &widgetEnvelope{})
+
+		assert.True(t, errors.Is(err, resource.ErrMissingID))
+	})
+
+	t.Run("should set the default organisation if the resource has none", func(t *testing.T) {
+		organisationID := uuid.New()
+		sent := newWidget(widgetID, "gear")
+		sent.Data.OrganisationID = uuid.Nil
+
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return body, nil
+			},
+		}, "widgets", "v1/widgets/", resource.WithDefaultOrganisationID(organisationID))
+
+		var response widgetEnvelope
+		err := endpoint.Create(context.Background(), widgetID, sent, &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, organisationID, response.Data.OrganisationID)
+		assert.Equal(t, "gear", response.Data.Attributes["name"])
+		assert.Equal(t, uuid.Nil, sent.Data.OrganisationID)
+	})
+
+	t.Run("should keep the organisation of the resource", func(t *testing.T) {
+		sent := newWidget(widgetID, "gear")
+
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return body, nil
+			},
+		}, "widgets", "v1/widgets/", resource.WithDefaultOrganisationID(uuid.New()))
+
+		var response widgetEnvelope
+		err := endpoint.Create(context.Background(), widgetID, sent, &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, sent.Data.OrganisationID, response.Data.OrganisationID)
+	})
+}
+
+func TestEndpoint_Update(t *testing.T) {
+
+	widgetID := uuid.New()
+
+	t.Run("should patch the resource", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockPatch: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				assert.Equal(t, "v1/widgets/"+widgetID.String(), path)
+				return body, nil
+			},
+		}, "widgets", "v1/widgets/")
+
+		var response widgetEnvelope
+		err := endpoint.Update(context.Background(), widgetID, 0, newWidget(widgetID, "gear"), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "gear", response.Data.Attributes["name"])
+	})
+
+	t.Run("should not call the API if the version is negative", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{}, "widgets", "v1/widgets/")
+
+		err := endpoint.Update(context.Background(), widgetID, -1, newWidget(widgetID, "gear"), &widgetEnvelope{})
+
+		assert.True(t, errors.Is(err, resource.ErrInvalidVersion))
+	})
+}
+
+func TestEndpoint_Delete(t *testing.T) {
+
+	widgetID := uuid.New()
+
+	t.Run("should send the version", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockDelete: func(ctx context.Context, path string) error {
+				assert.Equal(t, "v1/widgets/"+widgetID.String()+"?version=12", path)
+				return nil
+			},
+		}, "widgets", "v1/widgets/")
+
+		assert.Nil(t, endpoint.Delete(context.Background(), widgetID, 12))
+	})
+
+	t.Run("should not call the API if the version is negative", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{}, "widgets", "v1/widgets/")
+
+		err := endpoint.Delete(context.Background(), widgetID, -1)
+
+		assert.True(t, errors.Is(err, resource.ErrInvalidVersion))
+	})
+
+	t.Run("should treat a missing resource as deleted when enabled", func(t *testing.T) {
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockDelete: func(ctx context.Context, path string) error {
+				return &client.APIError{StatusCode: http.StatusNotFound}
+			},
+		}, "widgets", "v1/widgets/", resource.WithNotFoundAsDeleted())
+
+		assert.Nil(t, endpoint.Delete(context.Background(), widgetID, 0))
+	})
+
+	t.Run("should delete the latest version", func(t *testing.T) {
+		versions := []int{3, 4}
+		var deleted []int
+
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				current := newWidget(widgetID, "gear")
+				current.Data.Version = versions[len(deleted)]
+				return json.Marshal(current)
+			},
+			MockDelete: func(ctx context.Context, path string) error {
+				u, err := url.Parse(path)
+				require.NoError(t, err)
+				version, err := strconv.Atoi(u.Query().Get("version"))
+				require.NoError(t, err)
+				deleted = append(deleted, version)
+
+				if len(deleted) == 1 {
+					return &client.APIError{StatusCode: http.StatusConflict}
+				}
+
+				return nil
+			},
+		}, "widgets", "v1/widgets/")
+
+		assert.Nil(t, endpoint.DeleteLatest(context.Background(), widgetID, 3))
+		assert.Equal(t, []int{3, 4}, deleted)
+	})
+}
+
+func TestEndpoint_WithTracer(t *testing.T) {
+
+	widgetID := uuid.New()
+
+	t.Run("should record a span named after the resource", func(t *testing.T) {
+		exporter := tracing.NewInMemoryExporter()
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				return json.Marshal(newWidget(widgetID, "gear"))
+			},
+		}, "widgets", "v1/widgets/", resource.WithTracer(tracing.NewTracer(exporter)))
+
+		require.NoError(t, endpoint.Fetch(context.Background(), widgetID, &widgetEnvelope{}))
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "widgets.fetch", spans[0].Name)
+		assert.Equal(t, widgetID.String(), spans[0].Attributes[tracing.AttributeResourceID])
+	})
+
+	t.Run("should record the ID with the given attribute", func(t *testing.T) {
+		exporter := tracing.NewInMemoryExporter()
+		endpoint := resource.NewEndpoint(&mockedHttpClient{
+			MockPatch: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return body, nil
+			},
+		}, "widgets", "v1/widgets/", resource.WithTracer(tracing.NewTracer(exporter)), resource.WithIDAttribute("widget_id"))
+
+		require.NoError(t, endpoint.Update(context.Background(), widgetID, 0, newWidget(widgetID, "gear"), &widgetEnvelope{}))
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "widgets.update", spans[0].Name)
+		assert.Equal(t, widgetID.String(), spans[0].Attributes["widget_id"])
+	})
+}
@@ -0,0 +1,160 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"net/url"
+)
+
+// Operations are the operations of the services of this library, each with a WithContext variant that binds
+// its requests to ctx. The request bodies are the create and update envelopes of the model and the resources
+// are decoded into out, e.g. a *model.OrganisationApiResponse
+type Operations interface {
+	Fetch(id uuid.UUID, out interface{}) error
+	FetchWithContext(ctx context.Context, id uuid.UUID, out interface{}) error
+	Create(body interface{}, out interface{}) error
+	CreateWithContext(ctx context.Context, body interface{}, out interface{}) error
+	CreateOrGet(body interface{}, out interface{}) (bool, error)
+	CreateOrGetWithContext(ctx context.Context, body interface{}, out interface{}) (bool, error)
+	Update(body interface{}, out interface{}) error
+	UpdateWithContext(ctx context.Context, body interface{}, out interface{}) error
+	Delete(id uuid.UUID, version int) error
+	DeleteWithContext(ctx context.Context, id uuid.UUID, version int) error
+	DeleteLatest(id uuid.UUID, maxAttempts int) error
+	DeleteLatestWithContext(ctx context.Context, id uuid.UUID, maxAttempts int) error
+	List(query url.Values, out interface{}) error
+	ListWithContext(ctx context.Context, query url.Values, out interface{}) error
+	ListAll(query url.Values) *Iterator
+	ListAllWithContext(ctx context.Context, query url.Values) *Iterator
+}
+
+// Service implements Operations with an Endpoint. The ID and the version of a resource to create or update are
+// read from its body
+type Service struct {
+	endpoint *Endpoint
+}
+
+// NewService creates a Service
+func NewService(endpoint *Endpoint) *Service {
+	return &Service{endpoint: endpoint}
+}
+
+// Fetch decodes the resource with the given ID into out
+func (s *Service) Fetch(id uuid.UUID, out interface{}) error {
+	return s.FetchWithContext(context.Background(), id, out)
+}
+
+// FetchWithContext is like Fetch. The request is bound to ctx
+func (s *Service) FetchWithContext(ctx context.Context, id uuid.UUID, out interface{}) error {
+	return s.endpoint.Fetch(ctx, id, out)
+}
+
+// Create creates the resource of body and decodes it into out. An existing resource with the same ID and
+// attributes is decoded instead, see Endpoint.Create
+func (s *Service) Create(body interface{}, out interface{}) error {
+	return s.CreateWithContext(context.Background(), body, out)
+}
+
+// CreateWithContext is like Create. Every request is bound to ctx
+func (s *Service) CreateWithContext(ctx context.Context, body interface{}, out interface{}) error {
+	jsonBody, envelope, err := decodeBody(body)
+
+	if err != nil {
+		return err
+	}
+
+	return s.endpoint.Create(ctx, envelope.Data.ID, jsonBody, out)
+}
+
+// CreateOrGet creates the resource of body or, if one with the same ID already exists, fetches it whatever its
+// attributes. It reports whether the resource was created. The resource must have an ID
+func (s *Service) CreateOrGet(body interface{}, out interface{}) (bool, error) {
+	return s.CreateOrGetWithContext(context.Background(), body, out)
+}
+
+// CreateOrGetWithContext is like CreateOrGet. Every request is bound to ctx
+func (s *Service) CreateOrGetWithContext(ctx context.Context, body interface{}, out interface{}) (bool, error) {
+	jsonBody, envelope, err := decodeBody(body)
+
+	if err != nil {
+		return false, err
+	}
+
+	return s.endpoint.CreateOrFetch(ctx, envelope.Data.ID, jsonBody, out)
+}
+
+// Update sends the update request body and decodes the updated resource into out. The version of the resource
+// must be the current one, otherwise the API responds with a conflict that can be checked with client.IsConflict
+func (s *Service) Update(body interface{}, out interface{}) error {
+	return s.UpdateWithContext(context.Background(), body, out)
+}
+
+// UpdateWithContext is like Update. The request is bound to ctx
+func (s *Service) UpdateWithContext(ctx context.Context, body interface{}, out interface{}) error {
+	jsonBody, envelope, err := decodeBody(body)
+
+	if err != nil {
+		return err
+	}
+
+	return s.endpoint.Update(ctx, envelope.Data.ID, envelope.Data.Version, jsonBody, out)
+}
+
+// Delete deletes the resource with the given ID. The version must be the current one
+func (s *Service) Delete(id uuid.UUID, version int) error {
+	return s.DeleteWithContext(context.Background(), id, version)
+}
+
+// DeleteWithContext is like Delete. The request is bound to ctx
+func (s *Service) DeleteWithContext(ctx context.Context, id uuid.UUID, version int) error {
+	return s.endpoint.Delete(ctx, id, model.Version(version))
+}
+
+// DeleteLatest fetches the current version of a resource and deletes it, see Endpoint.DeleteLatest
+func (s *Service) DeleteLatest(id uuid.UUID, maxAttempts int) error {
+	return s.DeleteLatestWithContext(context.Background(), id, maxAttempts)
+}
+
+// DeleteLatestWithContext is like DeleteLatest. Every request is bound to ctx
+func (s *Service) DeleteLatestWithContext(ctx context.Context, id uuid.UUID, maxAttempts int) error {
+	return s.endpoint.DeleteLatest(ctx, id, maxAttempts)
+}
+
+// List decodes a page of resources into out. The query holds the page[...] and filter[...] parameters
+func (s *Service) List(query url.Values, out interface{}) error {
+	return s.ListWithContext(context.Background(), query, out)
+}
+
+// ListWithContext is like List. The request is bound to ctx
+func (s *Service) ListWithContext(ctx context.Context, query url.Values, out interface{}) error {
+	return s.endpoint.List(ctx, query, out)
+}
+
+// ListAll returns an Iterator that walks all the pages of resources, starting from the page[number] of query
+func (s *Service) ListAll(query url.Values) *Iterator {
+	return s.ListAllWithContext(context.Background(), query)
+}
+
+// ListAllWithContext is like ListAll. Every page request is bound to ctx
+func (s *Service) ListAllWithContext(ctx context.Context, query url.Values) *Iterator {
+	return s.endpoint.Iterate(ctx, query)
+}
+
+// decodeBody encodes a request body and decodes the ID and the version of its resource. The encoded body is
+// sent as it is, so it is only encoded once
+func decodeBody(body interface{}) (json.RawMessage, versionEnvelope, error) {
+	var envelope versionEnvelope
+	jsonBody, err := json.Marshal(body)
+
+	if err != nil {
+		return nil, envelope, err
+	}
+
+	if err := json.Unmarshal(jsonBody, &envelope); err != nil {
+		return nil, envelope, err
+	}
+
+	return jsonBody, envelope, nil
+}
@@ -0,0 +1,92 @@
+package resource_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/client"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/resources/resource"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestService_Create(t *testing.T) {
+
+	widgetID := uuid.New()
+
+	t.Run("should fetch the existing resource with the ID of the body", func(t *testing.T) {
+		existing := newWidget(widgetID, "sprocket")
+		service := resource.NewService(resource.NewEndpoint(&mockedHttpClient{
+			MockPost: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				return nil, &client.APIError{StatusCode: http.StatusConflict}
+			},
+			MockGet: func(ctx context.Context, path string) ([]byte, error) {
+				assert.Equal(t, "v1/widgets/"+widgetID.String(), path)
+				return json.Marshal(existing)
+			},
+		}, "widgets", "v1/widgets/"))
+
+		var response widgetEnvelope
+		created, err := service.CreateOrGet(newWidget(widgetID, "gear"), &response)
+
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Equal(t, existing, &response)
+	})
+
+	t.Run("should require an ID in the body on create or get", func(t *testing.T) {
+		service := resource.NewService(resource.NewEndpoint(&mockedHttpClient{}, "widgets", "v1/widgets/"))
+
+		_, err := service.CreateOrGet(newWidget(uuid.Nil, "gear"), &widgetEnvelope{})
+
+		assert.True(t, errors.Is(err, resource.ErrMissingID))
+	})
+}
+
+func TestService_Update(t *testing.T) {
+
+	widgetID := uuid.New()
+
+	t.Run("should patch the resource with the ID of the body", func(t *testing.T) {
+		service := resource.NewService(resource.NewEndpoint(&mockedHttpClient{
+			MockPatch: func(ctx context.Context, path string, body []byte) ([]byte, error) {
+				assert.Equal(t, "v1/widgets/"+widgetID.String(), path)
+				return body, nil
+			},
+		}, "widgets", "v1/widgets/"))
+
+		var response widgetEnvelope
+		err := service.Update(newWidget(widgetID, "gear"), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "gear", response.Data.Attributes["name"])
+	})
+
+	t.Run("should not call the API if the version of the body is negative", func(t *testing.T) {
+		service := resource.NewService(resource.NewEndpoint(&mockedHttpClient{}, "widgets", "v1/widgets/"))
+		widgetToUpdate := newWidget(widgetID, "gear")
+		widgetToUpdate.Data.Version = -1
+
+		err := service.Update(widgetToUpdate, &widgetEnvelope{})
+
+		assert.True(t, errors.Is(err, resource.ErrInvalidVersion))
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+
+	widgetID := uuid.New()
+
+	t.Run("should send the version", func(t *testing.T) {
+		service := resource.NewService(resource.NewEndpoint(&mockedHttpClient{
+			MockDelete: func(ctx context.Context, path string) error {
+				assert.Equal(t, "v1/widgets/"+widgetID.String()+"?version=3", path)
+				return nil
+			},
+		}, "widgets", "v1/widgets/"))
+
+		assert.Nil(t, service.Delete(widgetID, 3))
+	})
+}
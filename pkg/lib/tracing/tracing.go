@@ -0,0 +1,219 @@
+// Package tracing records spans of the lib's operations and propagates them with the W3C traceparent header.
+// The model follows OpenTelemetry, so a Tracer can be backed by an OpenTelemetry tracer
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Attribute keys set on the spans of the lib
+const (
+	AttributeOperation      = "form3.operation"
+	AttributeAccountID      = "form3.account_id"
+	AttributeOrganisationID = "form3.organisation_id"
+	AttributeResourceID     = "form3.resource_id"
+	AttributeAttempts       = "form3.attempts"
+	AttributeRetries        = "form3.retries"
+	AttributeHTTPMethod     = "http.method"
+	AttributeHTTPPath       = "http.path"
+	AttributeHTTPStatus     = "http.status_code"
+)
+
+// TraceID identifies a trace
+type TraceID [16]byte
+
+// String returns the hex encoding of the ID
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID isn't all zeros
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// String returns the hex encoding of the ID
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID isn't all zeros
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext identifies a span and is what is propagated to the API
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid reports whether both IDs are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Span is an operation being traced
+type Span interface {
+	// SpanContext returns the IDs of the span
+	SpanContext() SpanContext
+	// SetAttribute sets an attribute of the span
+	SetAttribute(key string, value interface{})
+	// SetError marks the span as failed. A nil error is ignored
+	SetError(err error)
+	// End finishes the span. Calls after the first are ignored
+	End()
+}
+
+// Tracer starts spans
+type Tracer interface {
+	// Start starts a span that is a child of the span in ctx, if any, and returns a context holding the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a context holding a span context, e.g. the one of an incoming request, so the
+// spans started with it are part of the same trace
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context held by ctx, or an invalid one
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	sc, _ := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc
+}
+
+// SpanData is a finished span, as handed to an Exporter
+type SpanData struct {
+	Name         string
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]interface{}
+	Err          error
+}
+
+// Exporter receives the spans when they end
+type Exporter interface {
+	Export(span SpanData)
+}
+
+// recordingTracer is the Tracer of NewTracer
+type recordingTracer struct {
+	exporter Exporter
+}
+
+// NewTracer creates a Tracer that hands every finished span to the exporter
+func NewTracer(exporter Exporter) Tracer {
+	return &recordingTracer{exporter: exporter}
+}
+
+// Start starts a span
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent := SpanContextFromContext(ctx)
+	span := &recordingSpan{
+		exporter: t.exporter,
+		data: SpanData{
+			Name:       name,
+			StartTime:  time.Now(),
+			Attributes: map[string]interface{}{},
+		},
+	}
+
+	if parent.IsValid() {
+		span.data.SpanContext.TraceID = parent.TraceID
+		span.data.SpanContext.Sampled = parent.Sampled
+		span.data.ParentSpanID = parent.SpanID
+	} else {
+		rand.Read(span.data.SpanContext.TraceID[:])
+		span.data.SpanContext.Sampled = true
+	}
+
+	rand.Read(span.data.SpanContext.SpanID[:])
+
+	return ContextWithSpanContext(ctx, span.data.SpanContext), span
+}
+
+// recordingSpan records its data until it ends
+type recordingSpan struct {
+	exporter Exporter
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+func (s *recordingSpan) SpanContext() SpanContext {
+	return s.data.SpanContext
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended {
+		s.data.Attributes[key] = value
+	}
+}
+
+func (s *recordingSpan) SetError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.ended {
+		s.data.Err = err
+	}
+}
+
+func (s *recordingSpan) End() {
+	s.mu.Lock()
+
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.exporter.Export(data)
+}
+
+// noopTracer starts spans that record nothing
+type noopTracer struct{}
+
+// NoopTracer returns a Tracer that records nothing. Used when no tracer is configured
+func NoopTracer() Tracer {
+	return noopTracer{}
+}
+
+// Start returns ctx unchanged, so a span context already in it is still propagated
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{sc: SpanContextFromContext(ctx)}
+}
+
+type noopSpan struct {
+	sc SpanContext
+}
+
+func (s noopSpan) SpanContext() SpanContext                 { return s.sc }
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) SetError(err error)                         {}
+func (noopSpan) End()                                       {}
@@ -0,0 +1,93 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+
+	t.Run("should parse a valid traceparent", func(t *testing.T) {
+		sc, err := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		require.NoError(t, err)
+
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", sc.TraceID.String())
+		assert.Equal(t, "00f067aa0ba902b7", sc.SpanID.String())
+		assert.True(t, sc.Sampled)
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.TraceParent())
+	})
+
+	t.Run("should reject an invalid traceparent", func(t *testing.T) {
+		for _, value := range []string{
+			"",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+			"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+			"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		} {
+			_, err := tracing.ParseTraceParent(value)
+			assert.True(t, errors.Is(err, tracing.ErrInvalidTraceParent), value)
+		}
+	})
+}
+
+func TestNewTracer(t *testing.T) {
+
+	t.Run("should export the finished spans with their parents", func(t *testing.T) {
+		exporter := tracing.NewInMemoryExporter()
+		tracer := tracing.NewTracer(exporter)
+
+		ctx, parent := tracer.Start(context.Background(), "accounts.fetch")
+		_, child := tracer.Start(ctx, "HTTP GET")
+		child.SetAttribute(tracing.AttributeHTTPStatus, 404)
+		child.SetError(errors.New("not found"))
+		child.End()
+		parent.End()
+		parent.End()
+
+		spans := exporter.Spans()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "HTTP GET", spans[0].Name)
+		assert.Equal(t, 404, spans[0].Attributes[tracing.AttributeHTTPStatus])
+		assert.EqualError(t, spans[0].Err, "not found")
+		assert.Equal(t, "accounts.fetch", spans[1].Name)
+		assert.Equal(t, spans[1].SpanContext.TraceID, spans[0].SpanContext.TraceID)
+		assert.Equal(t, spans[1].SpanContext.SpanID, spans[0].ParentSpanID)
+		assert.False(t, spans[1].ParentSpanID.IsValid())
+		assert.False(t, spans[0].EndTime.Before(spans[0].StartTime))
+	})
+
+	t.Run("should continue the trace of an incoming request", func(t *testing.T) {
+		exporter := tracing.NewInMemoryExporter()
+		remote, err := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		require.NoError(t, err)
+
+		_, span := tracing.NewTracer(exporter).Start(tracing.ContextWithSpanContext(context.Background(), remote), "accounts.list")
+		span.End()
+
+		assert.Equal(t, remote.TraceID, exporter.Spans()[0].SpanContext.TraceID)
+		assert.Equal(t, remote.SpanID, exporter.Spans()[0].ParentSpanID)
+
+		exporter.Reset()
+		assert.Empty(t, exporter.Spans())
+	})
+
+	t.Run("should propagate the span context without a tracer", func(t *testing.T) {
+		remote, err := tracing.ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+		require.NoError(t, err)
+
+		ctx, span := tracing.NoopTracer().Start(tracing.ContextWithSpanContext(context.Background(), remote), "accounts.list")
+		header := http.Header{}
+		tracing.Inject(header, tracing.SpanContextFromContext(ctx))
+
+		assert.Equal(t, remote, span.SpanContext())
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", header.Get(tracing.TraceParentHeader))
+	})
+}
@@ -4,40 +4,125 @@ import (
 	"github.com/google/uuid"
 )
 
+// Account statuses
+const (
+	AccountStatusPending   = "pending"
+	AccountStatusConfirmed = "confirmed"
+	AccountStatusFailed    = "failed"
+	AccountStatusClosed    = "closed"
+)
+
+// Account classifications
+const (
+	AccountClassificationPersonal = "Personal"
+	AccountClassificationBusiness = "Business"
+)
+
 // AccountApiResponse struct represents the response from Form3 Accounts API
 type AccountApiResponse struct {
-	Data  Account
-	Links Links
+	Data  Account `json:"data"`
+	Links Links   `json:"links"`
+}
+
+// AccountListResponse struct represents the response from Form3 Accounts API when listing accounts
+type AccountListResponse struct {
+	Data  []Account `json:"data"`
+	Links Links     `json:"links"`
 }
 
 // AccountCreateRequest struct represents the request send to Form3 Accounts API to create an account
 type AccountCreateRequest struct {
-	Data Account
+	Data Account `json:"data"`
+}
+
+// AccountUpdateRequest struct represents the request send to Form3 Accounts API to update an account.
+// Version must be the current version of the account
+type AccountUpdateRequest struct {
+	Data Account `json:"data"`
 }
 
 // Account struct represents a Form3 Account
 type Account struct {
-	Attributes     AccountAttributes
-	ID             uuid.UUID
-	OrganisationID uuid.UUID `json:"organisation_id"`
-	Version        int
-	Type           string
-	CreatedOn      string `json:"created_on"`
-	ModifiedOn     string `json:"modified_on"`
+	Attributes     AccountAttributes `json:"attributes"`
+	ID             uuid.UUID         `json:"id"`
+	OrganisationID uuid.UUID         `json:"organisation_id"`
+	Version        Version           `json:"version"`
+	Type           string            `json:"type"`
+	CreatedOn      string            `json:"created_on,omitempty"`
+	ModifiedOn     string            `json:"modified_on,omitempty"`
 }
 
-// AccountAttributes struct represents the attributes of a Form3 Account
+// AccountAttributes struct represents the attributes of a Form3 Account. Optional booleans are pointers
+// so an unset value can be told apart from false
 type AccountAttributes struct {
-	AlternativeNames []string `json:"alternative_names"`
-	BankID           string   `json:"bank_id"`
-	BankIDCode       string   `json:"bank_id_code"`
-	BaseCurrency     string   `json:"base_currency"`
-	Bic              string
-	Country          string
-	Name             []string
+	AcceptanceQualifier        string                      `json:"acceptance_qualifier,omitempty"`
+	AccountClassification      string                      `json:"account_classification,omitempty"`
+	AccountMatchingOptOut      *bool                       `json:"account_matching_opt_out,omitempty"`
+	AccountNumber              string                      `json:"account_number,omitempty"`
+	AlternativeNames           []string                    `json:"alternative_names,omitempty"`
+	BankID                     string                      `json:"bank_id,omitempty"`
+	BankIDCode                 string                      `json:"bank_id_code,omitempty"`
+	BaseCurrency               string                      `json:"base_currency,omitempty"`
+	Bic                        string                      `json:"bic,omitempty"`
+	Country                    string                      `json:"country,omitempty"`
+	Iban                       string                      `json:"iban,omitempty"`
+	JointAccount               *bool                       `json:"joint_account,omitempty"`
+	Name                       []string                    `json:"name,omitempty"`
+	NameMatchingStatus         string                      `json:"name_matching_status,omitempty"`
+	OrganisationIdentification *OrganisationIdentification `json:"organisation_identification,omitempty"`
+	PrivateIdentification      *PrivateIdentification      `json:"private_identification,omitempty"`
+	ReferenceMask              string                      `json:"reference_mask,omitempty"`
+	SecondaryIdentification    string                      `json:"secondary_identification,omitempty"`
+	Status                     string                      `json:"status,omitempty"`
+	StatusReason               string                      `json:"status_reason,omitempty"`
+	Switched                   *bool                       `json:"switched,omitempty"`
+	UserDefinedData            []UserDefinedData           `json:"user_defined_data,omitempty"`
+	ValidationType             string                      `json:"validation_type,omitempty"`
+}
+
+// PrivateIdentification struct represents the identification of an account holder who is a person
+type PrivateIdentification struct {
+	Address        []string `json:"address,omitempty"`
+	BirthCountry   string   `json:"birth_country,omitempty"`
+	BirthDate      string   `json:"birth_date,omitempty"`
+	City           string   `json:"city,omitempty"`
+	Country        string   `json:"country,omitempty"`
+	Identification string   `json:"identification,omitempty"`
+}
+
+// OrganisationIdentification struct represents the identification of an account holder who is an organisation
+type OrganisationIdentification struct {
+	Actors         []OrganisationIdentificationActor `json:"actors,omitempty"`
+	Address        []string                          `json:"address,omitempty"`
+	City           string                            `json:"city,omitempty"`
+	Country        string                            `json:"country,omitempty"`
+	Identification string                            `json:"identification,omitempty"`
+}
+
+// OrganisationIdentificationActor struct represents a person acting on behalf of an organisation account holder
+type OrganisationIdentificationActor struct {
+	BirthDate string   `json:"birth_date,omitempty"`
+	Name      []string `json:"name,omitempty"`
+	Residency string   `json:"residency,omitempty"`
 }
 
-// Links struct represents the links included in a Form3 Accounts API response
+// UserDefinedData struct represents a key value pair stored with an account
+type UserDefinedData struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Links struct represents the links included in a Form3 Accounts API response. First, Next, Prev and Last
+// are only set on paginated responses
 type Links struct {
-	Self string
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// Bool returns a pointer to b. Used to set the optional boolean attributes
+func Bool(b bool) *bool {
+	return &b
 }
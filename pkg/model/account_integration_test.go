@@ -0,0 +1,73 @@
+//go:build integration
+// +build integration
+
+package model_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// Sends a request to the accountapi container and returns the status code and the raw response body
+func doAccountAPIRequest(t *testing.T, method string, url string, body []byte) (int, []byte) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/vnd.api+json")
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	payload, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return res.StatusCode, payload
+}
+
+func TestAccountApiResponse_AccountAPI(t *testing.T) {
+
+	// Like form3Integration_test.go, this runs from within the lib container.
+	// To run it from your host change accountsURL to "http://localhost:8080/v1/organisation/accounts"
+	accountsURL := "http://accountapi:8080/v1/organisation/accounts"
+	accountID := uuid.New()
+
+	request, err := json.Marshal(testUtils.GetAccountCreateRequest(accountID))
+	require.NoError(t, err)
+	statusCode, _ := doAccountAPIRequest(t, http.MethodPost, accountsURL, request)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	defer doAccountAPIRequest(t, http.MethodDelete, fmt.Sprintf("%s/%s?version=0", accountsURL, accountID), nil)
+
+	t.Run("should decode and encode a fetch response without losing fields", func(t *testing.T) {
+		statusCode, payload := doAccountAPIRequest(t, http.MethodGet, fmt.Sprintf("%s/%s", accountsURL, accountID), nil)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var response model.AccountApiResponse
+		require.NoError(t, json.Unmarshal(payload, &response))
+		encoded, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, string(payload), string(encoded))
+	})
+
+	t.Run("should decode and encode a list response without losing fields", func(t *testing.T) {
+		statusCode, payload := doAccountAPIRequest(t, http.MethodGet, accountsURL+"?page%5Bsize%5D=2", nil)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		var response model.AccountListResponse
+		require.NoError(t, json.Unmarshal(payload, &response))
+		encoded, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, string(payload), string(encoded))
+	})
+}
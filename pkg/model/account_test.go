@@ -0,0 +1,131 @@
+package model_test
+
+import (
+	"encoding/json"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/lib/iban"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/ioannisGiak89/accounts-api-client/testUtils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// Reads a Form3 Accounts API payload from the testdata folder. The fetch payload is the example response of the
+// "Fetch an account" endpoint of the Form3 API documentation, with valid IBAN check digits. The list payload is
+// written in the same shape. The model is checked against the responses of the accountapi container by
+// TestAccountApiResponse_AccountAPI, behind the integration build tag
+func readPayload(t *testing.T, name string) []byte {
+	payload, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	return payload
+}
+
+func TestAccountApiResponse_JSON(t *testing.T) {
+
+	t.Run("should decode all the attributes of a fetch response", func(t *testing.T) {
+		var response model.AccountApiResponse
+		err := json.Unmarshal(readPayload(t, "account_fetch_response.json"), &response)
+		require.NoError(t, err)
+
+		attributes := response.Data.Attributes
+		assert.Equal(t, "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc", response.Data.ID.String())
+		assert.Equal(t, "accounts", response.Data.Type)
+		assert.Equal(t, "41426819", attributes.AccountNumber)
+		assert.Equal(t, "GB16NWBK40030041426819", attributes.Iban)
+		assert.Equal(t, model.AccountClassificationPersonal, attributes.AccountClassification)
+		assert.Equal(t, model.Bool(false), attributes.JointAccount)
+		assert.Equal(t, model.Bool(false), attributes.AccountMatchingOptOut)
+		assert.Equal(t, model.Bool(false), attributes.Switched)
+		assert.Equal(t, model.AccountStatusConfirmed, attributes.Status)
+		assert.Equal(t, "A1B2C3D4", attributes.SecondaryIdentification)
+		assert.Equal(t, "supported", attributes.NameMatchingStatus)
+		assert.Equal(t, "card", attributes.ValidationType)
+		assert.Equal(t, "############", attributes.ReferenceMask)
+		assert.Equal(t, "same_day", attributes.AcceptanceQualifier)
+		assert.Equal(t, &model.PrivateIdentification{
+			Address:        []string{"10 Avenue des Champs"},
+			BirthCountry:   "GB",
+			BirthDate:      "2017-07-23",
+			City:           "London",
+			Country:        "GB",
+			Identification: "13YH458762",
+		}, attributes.PrivateIdentification)
+		assert.Equal(t, []model.UserDefinedData{
+			{Key: "Some account related key", Value: "Some account related value"},
+		}, attributes.UserDefinedData)
+		assert.Nil(t, attributes.OrganisationIdentification)
+	})
+
+	t.Run("should encode a fetch response back to the payload", func(t *testing.T) {
+		payload := readPayload(t, "account_fetch_response.json")
+
+		var response model.AccountApiResponse
+		require.NoError(t, json.Unmarshal(payload, &response))
+		encoded, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, string(payload), string(encoded))
+	})
+
+	t.Run("should encode a list response back to the payload", func(t *testing.T) {
+		payload := readPayload(t, "account_list_response.json")
+
+		var response model.AccountListResponse
+		require.NoError(t, json.Unmarshal(payload, &response))
+		encoded, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, string(payload), string(encoded))
+		assert.Len(t, response.Data, 2)
+		assert.Equal(t, model.Bool(true), response.Data[0].Attributes.JointAccount)
+		assert.Equal(t, "Jeff Page", response.Data[0].Attributes.OrganisationIdentification.Actors[0].Name[0])
+		assert.Nil(t, response.Data[1].Attributes.JointAccount)
+		assert.Equal(t, "/v1/organisation/accounts?page%5Bnumber%5D=1&page%5Bsize%5D=2", response.Links.Next)
+	})
+
+	t.Run("should have valid IBANs that match the bank details in the payloads", func(t *testing.T) {
+		var fetched model.AccountApiResponse
+		require.NoError(t, json.Unmarshal(readPayload(t, "account_fetch_response.json"), &fetched))
+		var listed model.AccountListResponse
+		require.NoError(t, json.Unmarshal(readPayload(t, "account_list_response.json"), &listed))
+
+		for _, account := range append(listed.Data, fetched.Data) {
+			if account.Attributes.Iban == "" {
+				continue
+			}
+
+			parsed, err := iban.Parse(account.Attributes.Iban)
+			require.NoError(t, err, account.Attributes.Iban)
+			assert.Equal(t, account.Attributes.BankID, parsed.BankID)
+			assert.Equal(t, account.Attributes.AccountNumber, parsed.AccountNumber)
+		}
+	})
+
+	t.Run("should omit unset optional attributes in a create request", func(t *testing.T) {
+		request := testUtils.GetAccountCreateRequest(testUtils.ParseUuid("ad27e265-9605-4b4b-a0e5-3003ea9cc4dc"))
+
+		encoded, err := json.Marshal(request)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{
+			"data": {
+				"attributes": {
+					"alternative_names": ["Some", "Alt", "Names"],
+					"bank_id": "400300",
+					"bank_id_code": "GBDSC",
+					"base_currency": "GBP",
+					"bic": "NWBKGB22",
+					"country": "GB",
+					"name": ["Samantha Holder"]
+				},
+				"id": "ad27e265-9605-4b4b-a0e5-3003ea9cc4dc",
+				"organisation_id": "eb0bd6f5-c3f5-44b2-b677-acd23cdde73c",
+				"type": "accounts",
+				"version": 0
+			}
+		}`, string(encoded))
+	})
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// OrganisationApiResponse struct represents the response from Form3 Organisation Units API
+type OrganisationApiResponse struct {
+	Data  Organisation `json:"data"`
+	Links Links        `json:"links"`
+}
+
+// OrganisationListResponse struct represents the response from Form3 Organisation Units API when listing
+// organisation units
+type OrganisationListResponse struct {
+	Data  []Organisation `json:"data"`
+	Links Links          `json:"links"`
+}
+
+// OrganisationCreateRequest struct represents the request send to Form3 Organisation Units API to create an
+// organisation unit
+type OrganisationCreateRequest struct {
+	Data Organisation `json:"data"`
+}
+
+// OrganisationUpdateRequest struct represents the request send to Form3 Organisation Units API to update an
+// organisation unit. Version must be the current version of the organisation unit
+type OrganisationUpdateRequest struct {
+	Data Organisation `json:"data"`
+}
+
+// Organisation struct represents a Form3 organisation unit. OrganisationID is the organisation it belongs to
+type Organisation struct {
+	Attributes     OrganisationAttributes `json:"attributes"`
+	ID             uuid.UUID              `json:"id"`
+	OrganisationID uuid.UUID              `json:"organisation_id"`
+	Version        Version                `json:"version"`
+	Type           string                 `json:"type"`
+	CreatedOn      string                 `json:"created_on,omitempty"`
+	ModifiedOn     string                 `json:"modified_on,omitempty"`
+}
+
+// OrganisationAttributes struct represents the attributes of a Form3 organisation unit
+type OrganisationAttributes struct {
+	Name            string            `json:"name,omitempty"`
+	Country         string            `json:"country,omitempty"`
+	Address         []string          `json:"address,omitempty"`
+	City            string            `json:"city,omitempty"`
+	UserDefinedData []UserDefinedData `json:"user_defined_data,omitempty"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrInvalidVersion is returned by Version.Validate when the version is negative
+var ErrInvalidVersion = errors.New("model: invalid version")
+
+// Version is the version of a Form3 resource. It starts at 0 and is incremented by every update. Deletes and
+// updates must send the current version
+type Version int
+
+// Validate returns an error wrapping ErrInvalidVersion if the version can't be the version of a resource
+func (v Version) Validate() error {
+	if v < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidVersion, int(v))
+	}
+
+	return nil
+}
+
+// String returns the version as the decimal number the API expects in a query
+func (v Version) String() string {
+	return strconv.Itoa(int(v))
+}
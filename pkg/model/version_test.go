@@ -0,0 +1,23 @@
+package model_test
+
+import (
+	"errors"
+	"github.com/ioannisGiak89/accounts-api-client/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVersion_Validate(t *testing.T) {
+
+	t.Run("should accept the first and later versions", func(t *testing.T) {
+		assert.Nil(t, model.Version(0).Validate())
+		assert.Nil(t, model.Version(12).Validate())
+	})
+
+	t.Run("should reject a negative version", func(t *testing.T) {
+		err := model.Version(-1).Validate()
+
+		assert.True(t, errors.Is(err, model.ErrInvalidVersion))
+		assert.EqualError(t, err, "model: invalid version: -1")
+	})
+}
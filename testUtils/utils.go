@@ -51,6 +51,43 @@ func GetAccountCreateRequest(id uuid.UUID) *model.AccountCreateRequest {
 	}
 }
 
+// Returns a Form3 Organisation Units API response
+func GetOrganisationApiResponse(id uuid.UUID) *model.OrganisationApiResponse {
+	return &model.OrganisationApiResponse{
+		Data: model.Organisation{
+			Attributes: model.OrganisationAttributes{
+				Name:    "Holder Trading Ltd",
+				Country: "GB",
+				City:    "London",
+			},
+			ID:             id,
+			OrganisationID: ParseUuid("eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"),
+			Version:        0,
+			Type:           "organisations",
+			CreatedOn:      "2021-06-12T13:30:28.831Z",
+			ModifiedOn:     "2021-06-12T13:30:28.831Z",
+		},
+		Links: model.Links{Self: "/v1/organisation/units/" + id.String()},
+	}
+}
+
+// Returns a Form3 Organisation Units API create request
+func GetOrganisationCreateRequest(id uuid.UUID) *model.OrganisationCreateRequest {
+	return &model.OrganisationCreateRequest{
+		Data: model.Organisation{
+			Attributes: model.OrganisationAttributes{
+				Name:    "Holder Trading Ltd",
+				Country: "GB",
+				City:    "London",
+			},
+			ID:             id,
+			OrganisationID: ParseUuid("eb0bd6f5-c3f5-44b2-b677-acd23cdde73c"),
+			Version:        0,
+			Type:           "organisations",
+		},
+	}
+}
+
 func ParseUuid(id string) uuid.UUID {
 	uID, err := uuid.Parse(id)
 